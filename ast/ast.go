@@ -1,7 +1,10 @@
 package ast
 
+import "github.com/ganyariya/go_monkey/token"
+
 // AST のすべてのノードは Node のメソッドを実装する必要あり
 type Node interface {
 	TokenLiteral() string // **Token** の Literal (式ではない トークン自体のリテラル)
 	String() string
+	Pos() token.Position // ノードの先頭トークンのソースコード上の位置
 }
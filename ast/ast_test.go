@@ -28,3 +28,29 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestEqual(t *testing.T) {
+	ident := func(name string, line int) *IdentifierExpression {
+		return &IdentifierExpression{
+			Token: token.Token{Type: token.IDENTIFIER, Literal: name, Pos: token.Position{Line: line, Column: 1}},
+			Value: name,
+		}
+	}
+	plus := func(left, right Expression) *InfixExpression {
+		return &InfixExpression{Token: token.Token{Type: token.PLUS, Literal: "+"}, Left: left, Operator: "+", Right: right}
+	}
+
+	// 位置が違っても構造が同じなら等しい
+	if !Equal(plus(ident("a", 1), ident("b", 1)), plus(ident("a", 2), ident("b", 3))) {
+		t.Errorf("expressions at different positions should be equal")
+	}
+	if Equal(plus(ident("a", 1), ident("b", 1)), plus(ident("a", 1), ident("c", 1))) {
+		t.Errorf("expressions with different identifiers should not be equal")
+	}
+	if Equal(ident("a", 1), &StringLiteralExpression{Token: token.Token{Type: token.STRING, Literal: "a"}, Value: "a"}) {
+		t.Errorf("different node types should not be equal")
+	}
+	if !Equal(nil, nil) || Equal(ident("a", 1), nil) {
+		t.Errorf("nil nodes should only equal nil")
+	}
+}
@@ -0,0 +1,42 @@
+package ast
+
+import (
+	"strings"
+
+	"github.com/ganyariya/go_monkey/token"
+)
+
+// `// ...` や `/* ... */` のコメント
+// 評価には影響しないが、フォーマッタやドキュメント生成のために直後の文にトリビアとして保持される
+type Comment struct {
+	Token    token.Token // token.COMMENT (Literal は `//` や `/*` `*/` を含む)
+	Trailing bool        // 直前のトークンと同じ行に書かれたコメント（`let a = 1; // ...`）
+}
+
+func (c *Comment) Pos() token.Position { return c.Token.Pos }
+
+// コメントの最終行
+func (c *Comment) EndLine() int {
+	return c.Token.Pos.Line + strings.Count(c.Token.Literal, "\n")
+}
+
+// `//` や `/*` `*/` を取り除いたコメントの本文
+func (c *Comment) Text() string {
+	literal := c.Token.Literal
+	if strings.HasPrefix(literal, "//") {
+		return strings.TrimPrefix(strings.TrimPrefix(literal, "//"), " ")
+	}
+	literal = strings.TrimSuffix(strings.TrimPrefix(literal, "/*"), "*/")
+	return strings.TrimSpace(literal)
+}
+
+/*
+文に付与されるトリビア
+すべての Statement に埋め込み、その文の直前にあるコメントを保持する
+*/
+type Trivia struct {
+	Comments []*Comment
+}
+
+func (t *Trivia) LeadingComments() []*Comment            { return t.Comments }
+func (t *Trivia) SetLeadingComments(comments []*Comment) { t.Comments = comments }
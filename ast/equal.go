@@ -0,0 +1,94 @@
+package ast
+
+import (
+	"reflect"
+
+	"github.com/ganyariya/go_monkey/token"
+)
+
+var (
+	positionType = reflect.TypeOf(token.Position{})
+	triviaType   = reflect.TypeOf(Trivia{})
+	commentsType = reflect.TypeOf([]*Comment{})
+)
+
+/*
+2 つの Node が構造的に等しいか判定する
+ソースコード上の位置とコメントは比較しないため、別の場所に書かれた同じ式も等しくなる
+（quote(1 + 2) == quote(1 + 2) は true）
+*/
+func Equal(a, b Node) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return equalValue(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func equalValue(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Type() {
+	case positionType, triviaType, commentsType:
+		return true
+	}
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() && b.IsNil()
+		}
+		return equalValue(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !equalValue(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		return equalMap(a, b)
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.String:
+		return a.String() == b.String()
+	}
+	return false
+}
+
+// キーが Node の map にも対応できるよう、キーも構造で比較して対応するペアを探す
+func equalMap(a, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	iter := a.MapRange()
+	for iter.Next() {
+		found := false
+		otherIter := b.MapRange()
+		for otherIter.Next() {
+			if equalValue(iter.Key(), otherIter.Key()) && equalValue(iter.Value(), otherIter.Value()) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
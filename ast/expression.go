@@ -28,6 +28,7 @@ type IdentifierExpression struct {
 
 func (i *IdentifierExpression) expressionNode()      {}
 func (i *IdentifierExpression) TokenLiteral() string { return i.Token.Literal }
+func (i *IdentifierExpression) Pos() token.Position  { return i.Token.Pos }
 func (i *IdentifierExpression) String() string       { return i.Value } // for Debug
 
 // **Token 以外の値である** Value が構文解析とそのあとで「実際に使う」値っぽい（整数に変換しているため）
@@ -39,8 +40,19 @@ type IntegerLiteralExpression struct {
 
 func (i *IntegerLiteralExpression) expressionNode()      {}
 func (i *IntegerLiteralExpression) TokenLiteral() string { return i.Token.Literal }
+func (i *IntegerLiteralExpression) Pos() token.Position  { return i.Token.Pos }
 func (i *IntegerLiteralExpression) String() string       { return i.Token.Literal } // for Debug
 
+type FloatLiteralExpression struct {
+	Token token.Token // token.FLOAT
+	Value float64     // 3.14 (Token.Literal を変換する)
+}
+
+func (f *FloatLiteralExpression) expressionNode()      {}
+func (f *FloatLiteralExpression) TokenLiteral() string { return f.Token.Literal }
+func (f *FloatLiteralExpression) Pos() token.Position  { return f.Token.Pos }
+func (f *FloatLiteralExpression) String() string       { return f.Token.Literal }
+
 type BooleanExpression struct {
 	Token token.Token // token.TRUE or FALSE
 	Value bool
@@ -48,8 +60,19 @@ type BooleanExpression struct {
 
 func (b *BooleanExpression) expressionNode()      {}
 func (b *BooleanExpression) TokenLiteral() string { return b.Token.Literal }
+func (b *BooleanExpression) Pos() token.Position  { return b.Token.Pos }
 func (b *BooleanExpression) String() string       { return b.Token.Literal }
 
+// null リテラル
+type NullLiteralExpression struct {
+	Token token.Token // token.NULL
+}
+
+func (n *NullLiteralExpression) expressionNode()      {}
+func (n *NullLiteralExpression) TokenLiteral() string { return n.Token.Literal }
+func (n *NullLiteralExpression) Pos() token.Position  { return n.Token.Pos }
+func (n *NullLiteralExpression) String() string       { return n.Token.Literal }
+
 type StringLiteralExpression struct {
 	Token token.Token // token.STRING
 	Value string
@@ -57,8 +80,33 @@ type StringLiteralExpression struct {
 
 func (s *StringLiteralExpression) expressionNode()      {}
 func (s *StringLiteralExpression) TokenLiteral() string { return s.Token.Literal }
+func (s *StringLiteralExpression) Pos() token.Position  { return s.Token.Pos }
 func (s *StringLiteralExpression) String() string       { return s.Token.Literal }
 
+/*
+"hello ${name}!" のような補間を含む文字列
+Parts は StringLiteralExpression（文字列部分）と任意の式（補間部分）が順に並ぶ
+*/
+type InterpolatedStringExpression struct {
+	Token token.Token // token.STRING_HEAD
+	Parts []Expression
+}
+
+func (is *InterpolatedStringExpression) expressionNode()      {}
+func (is *InterpolatedStringExpression) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedStringExpression) Pos() token.Position  { return is.Token.Pos }
+func (is *InterpolatedStringExpression) String() string {
+	var out bytes.Buffer
+	for _, part := range is.Parts {
+		if str, ok := part.(*StringLiteralExpression); ok {
+			out.WriteString(str.Value)
+		} else {
+			out.WriteString(fmt.Sprintf("${%s}", part.String()))
+		}
+	}
+	return out.String()
+}
+
 type PrefixExpression struct {
 	Token    token.Token // token.MINUS, BANG
 	Operator string      // 前置演算子
@@ -67,6 +115,7 @@ type PrefixExpression struct {
 
 func (pe *PrefixExpression) expressionNode()      {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() token.Position  { return pe.Token.Pos }
 func (pe *PrefixExpression) String() string {
 	return fmt.Sprintf("(%s%s)", pe.Operator, pe.Right.String())
 }
@@ -80,10 +129,30 @@ type InfixExpression struct {
 
 func (ie *InfixExpression) expressionNode()      {}
 func (ie *InfixExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *InfixExpression) Pos() token.Position  { return ie.Token.Pos }
 func (ie *InfixExpression) String() string {
 	return fmt.Sprintf("(%s %s %s)", ie.Left.String(), ie.Operator, ie.Right.String())
 }
 
+/*
+代入式 `x = value` `x += value`
+let と異なり新しい束縛は作らず、外側のスコープも含めて最も近い既存の束縛を書き換える
+代入式の値は代入した値になる（`a = b = 1` は右結合）
+*/
+type AssignExpression struct {
+	Token    token.Token // token.ASSIGN, token.PLUS_ASSIGN, ...
+	Target   Expression  // 代入先（IdentifierExpression）
+	Operator string      // "=", "+=", ...
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) Pos() token.Position  { return ae.Token.Pos }
+func (ae *AssignExpression) String() string {
+	return fmt.Sprintf("(%s %s %s)", ae.Target.String(), ae.Operator, ae.Value.String())
+}
+
 /*
 if (condition) Consequence else Alternative
 Monkey で if は値を返す式だが、BlockStatement のそれぞれをIfExpression式の中に含む
@@ -98,6 +167,7 @@ type IfExpression struct {
 
 func (ie *IfExpression) expressionNode()      {}
 func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) Pos() token.Position  { return ie.Token.Pos }
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString("if")
@@ -114,17 +184,33 @@ func (ie *IfExpression) String() string {
 type FunctionExpression struct {
 	Token      token.Token // token.FUNCTION
 	Parameters []*IdentifierExpression
+	Defaults   map[string]Expression // デフォルト値をもつ仮引数 `fn(x, y = 2)`（呼び出し時に評価する）
+	Rest       *IdentifierExpression // 残りの実引数を配列で受け取る仮引数 `fn(first, ...rest)`
 	Body       *BlockStatement
+	Name       string // `let name = fn...` で束縛される名前（エラーメッセージに使う）
 }
 
 func (fe *FunctionExpression) expressionNode()      {}
 func (fe *FunctionExpression) TokenLiteral() string { return fe.Token.Literal }
+func (fe *FunctionExpression) Pos() token.Position  { return fe.Token.Pos }
 func (fe *FunctionExpression) String() string {
-	params := []string{}
-	for _, p := range fe.Parameters {
-		params = append(params, p.String())
+	return fmt.Sprintf("%s(%s)%s", fe.TokenLiteral(), FormatParameters(fe.Parameters, fe.Defaults, fe.Rest), fe.Body.String())
+}
+
+// 仮引数リストを `x, y = 2, ...rest` の形式で表示する
+func FormatParameters(params []*IdentifierExpression, defaults map[string]Expression, rest *IdentifierExpression) string {
+	out := []string{}
+	for _, p := range params {
+		if d, ok := defaults[p.Value]; ok {
+			out = append(out, fmt.Sprintf("%s = %s", p.String(), d.String()))
+		} else {
+			out = append(out, p.String())
+		}
 	}
-	return fmt.Sprintf("%s(%s)%s", fe.TokenLiteral(), strings.Join(params, ", "), fe.Body.String())
+	if rest != nil {
+		out = append(out, "..."+rest.String())
+	}
+	return strings.Join(out, ", ")
 }
 
 /*
@@ -139,6 +225,7 @@ type CallExpression struct {
 
 func (ce *CallExpression) expressionNode()      {}
 func (ce *CallExpression) TokenLiteral() string { return ce.Token.Literal }
+func (ce *CallExpression) Pos() token.Position  { return ce.Token.Pos }
 func (ce *CallExpression) String() string {
 	args := []string{}
 	for _, a := range ce.Arguments {
@@ -154,6 +241,7 @@ type ArrayLiteralExpression struct {
 
 func (a *ArrayLiteralExpression) expressionNode()      {}
 func (a *ArrayLiteralExpression) TokenLiteral() string { return a.Token.Literal }
+func (a *ArrayLiteralExpression) Pos() token.Position  { return a.Token.Pos }
 func (a *ArrayLiteralExpression) String() string {
 	elements := []string{}
 	for _, e := range a.Elements {
@@ -175,21 +263,71 @@ type IndexExpression struct {
 
 func (i *IndexExpression) expressionNode()      {}
 func (i *IndexExpression) TokenLiteral() string { return i.Token.Literal }
+func (i *IndexExpression) Pos() token.Position  { return i.Token.Pos }
 func (i *IndexExpression) String() string {
 	return fmt.Sprintf("(%s[%s])", i.Left.String(), i.Index.String())
 }
 
+/*
+obj.field （`obj["field"]` と同じ）
+呼び出し `obj.method(args)` では obj を self として束縛する
+*/
+type MemberExpression struct {
+	Token    token.Token // token.DOT
+	Object   Expression
+	Property *IdentifierExpression
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) Pos() token.Position  { return me.Token.Pos }
+func (me *MemberExpression) String() string {
+	return fmt.Sprintf("(%s.%s)", me.Object.String(), me.Property.String())
+}
+
+/*
+xs[Start:End] （Start と End はそれぞれ省略できる。省略したときは nil）
+負の添字は末尾から数える（`xs[-2:]` は最後の 2 要素）
+*/
+type SliceExpression struct {
+	Token token.Token // token.LBRACKET
+	Left  Expression
+	Start Expression
+	End   Expression
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) Pos() token.Position  { return se.Token.Pos }
+func (se *SliceExpression) String() string {
+	start, end := "", ""
+	if se.Start != nil {
+		start = se.Start.String()
+	}
+	if se.End != nil {
+		end = se.End.String()
+	}
+	return fmt.Sprintf("(%s[%s:%s])", se.Left.String(), start, end)
+}
+
+// ペアはソースコードに書かれた順に並ぶ（評価もこの順に行う）
 type HashLiteralExpression struct {
 	Token token.Token // token.LBRACE
-	Pairs map[Expression]Expression
+	Pairs []HashLiteralPair
+}
+
+type HashLiteralPair struct {
+	Key   Expression
+	Value Expression
 }
 
 func (h *HashLiteralExpression) expressionNode()      {}
 func (h *HashLiteralExpression) TokenLiteral() string { return h.Token.Literal }
+func (h *HashLiteralExpression) Pos() token.Position  { return h.Token.Pos }
 func (h *HashLiteralExpression) String() string {
 	pairs := []string{}
-	for k, v := range h.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s:%s", k.String(), v.String()))
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, fmt.Sprintf("%s:%s", pair.Key.String(), pair.Value.String()))
 	}
 	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
 }
@@ -202,6 +340,7 @@ type MacroExpression struct {
 
 func (m *MacroExpression) expressionNode()      {}
 func (m *MacroExpression) TokenLiteral() string { return m.Token.Literal }
+func (m *MacroExpression) Pos() token.Position  { return m.Token.Pos }
 func (m *MacroExpression) String() string {
 	params := []string{}
 	for _, p := range m.Parameters {
@@ -209,3 +348,77 @@ func (m *MacroExpression) String() string {
 	}
 	return fmt.Sprintf("%s(%s)%s", m.TokenLiteral(), strings.Join(params, ", "), m.Body.String())
 }
+
+// 構文エラーのため解析できなかった式（Token は解析に失敗した位置のトークン）
+type BadExpression struct {
+	Token token.Token
+}
+
+func (b *BadExpression) expressionNode()      {}
+func (b *BadExpression) TokenLiteral() string { return b.Token.Literal }
+func (b *BadExpression) Pos() token.Position  { return b.Token.Pos }
+func (b *BadExpression) String() string       { return "<bad expression>" }
+
+/*
+match (Subject) { pattern => expr, pattern if guard => expr, ... }
+上から順にパターンと照合し、最初に一致した（ガードがあれば真になった）分岐の式の値になる
+*/
+type MatchExpression struct {
+	Token   token.Token // token.MATCH
+	Subject Expression
+	Arms    []*MatchArm
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) Pos() token.Position  { return me.Token.Pos }
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+	return fmt.Sprintf("match (%s) { %s }", me.Subject.String(), strings.Join(arms, ", "))
+}
+
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression // `if cond`（なければ nil）
+	Body    Expression
+}
+
+func (ma *MatchArm) TokenLiteral() string { return ma.Pattern.TokenLiteral() }
+func (ma *MatchArm) Pos() token.Position  { return ma.Pattern.Pos() }
+func (ma *MatchArm) String() string {
+	if ma.Guard != nil {
+		return fmt.Sprintf("%s if %s => %s", ma.Pattern.String(), ma.Guard.String(), ma.Body.String())
+	}
+	return fmt.Sprintf("%s => %s", ma.Pattern.String(), ma.Body.String())
+}
+
+/*
+try { Block } catch (Param) { Catch } finally { Finally }
+catch と finally はどちらか一方を省略できる（省略したものは nil）
+値は Block（エラーが起きたときは Catch）の値になる
+*/
+type TryExpression struct {
+	Token   token.Token // token.TRY
+	Block   *BlockStatement
+	Param   *IdentifierExpression
+	Catch   *BlockStatement
+	Finally *BlockStatement
+}
+
+func (te *TryExpression) expressionNode()      {}
+func (te *TryExpression) TokenLiteral() string { return te.Token.Literal }
+func (te *TryExpression) Pos() token.Position  { return te.Token.Pos }
+func (te *TryExpression) String() string {
+	var out bytes.Buffer
+	out.WriteString("try " + te.Block.String())
+	if te.Catch != nil {
+		out.WriteString(fmt.Sprintf(" catch (%s) %s", te.Param.String(), te.Catch.String()))
+	}
+	if te.Finally != nil {
+		out.WriteString(" finally " + te.Finally.String())
+	}
+	return out.String()
+}
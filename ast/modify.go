@@ -20,36 +20,95 @@ func Modify(node Node, modifier ModifierFunc) Node {
 	case *ReturnStatement:
 		node.ReturnValue, _ = Modify(node.ReturnValue, modifier).(Expression)
 	case *LetStatement:
+		if node.Pattern != nil {
+			node.Pattern, _ = Modify(node.Pattern, modifier).(Pattern)
+		}
 		node.Value, _ = Modify(node.Value, modifier).(Expression)
 	case *InfixExpression:
 		node.Left, _ = Modify(node.Left, modifier).(Expression)
 		node.Right, _ = Modify(node.Right, modifier).(Expression)
+	case *WhileStatement:
+		node.Condition, _ = Modify(node.Condition, modifier).(Expression)
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+	case *ForStatement:
+		node.Iterable, _ = Modify(node.Iterable, modifier).(Expression)
+		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+	case *MatchExpression:
+		node.Subject, _ = Modify(node.Subject, modifier).(Expression)
+		for i, arm := range node.Arms {
+			node.Arms[i], _ = Modify(arm, modifier).(*MatchArm)
+		}
+	case *MatchArm:
+		node.Pattern, _ = Modify(node.Pattern, modifier).(Pattern)
+		if node.Guard != nil {
+			node.Guard, _ = Modify(node.Guard, modifier).(Expression)
+		}
+		node.Body, _ = Modify(node.Body, modifier).(Expression)
+	case *LiteralPattern:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+	case *ArrayPattern:
+		for i, element := range node.Elements {
+			node.Elements[i], _ = Modify(element, modifier).(Pattern)
+		}
+	case *HashPattern:
+		for i, value := range node.Values {
+			node.Values[i], _ = Modify(value, modifier).(Pattern)
+		}
+	case *AssignExpression:
+		node.Target, _ = Modify(node.Target, modifier).(Expression)
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
 	case *PrefixExpression:
 		node.Right, _ = Modify(node.Right, modifier).(Expression)
 	case *IndexExpression:
 		node.Left, _ = Modify(node.Left, modifier).(Expression)
 		node.Index, _ = Modify(node.Index, modifier).(Expression)
+	case *MemberExpression:
+		node.Object, _ = Modify(node.Object, modifier).(Expression)
+	case *SliceExpression:
+		node.Left, _ = Modify(node.Left, modifier).(Expression)
+		if node.Start != nil {
+			node.Start, _ = Modify(node.Start, modifier).(Expression)
+		}
+		if node.End != nil {
+			node.End, _ = Modify(node.End, modifier).(Expression)
+		}
+	case *ThrowStatement:
+		node.Value, _ = Modify(node.Value, modifier).(Expression)
+	case *TryExpression:
+		node.Block, _ = Modify(node.Block, modifier).(*BlockStatement)
+		if node.Catch != nil {
+			node.Catch, _ = Modify(node.Catch, modifier).(*BlockStatement)
+		}
+		if node.Finally != nil {
+			node.Finally, _ = Modify(node.Finally, modifier).(*BlockStatement)
+		}
 	case *IfExpression:
 		node.Condition, _ = Modify(node.Condition, modifier).(Expression)
 		node.Consequence, _ = Modify(node.Consequence, modifier).(*BlockStatement)
-		node.Alternative, _ = Modify(node.Alternative, modifier).(*BlockStatement)
+		if node.Alternative != nil {
+			node.Alternative, _ = Modify(node.Alternative, modifier).(*BlockStatement)
+		}
 	case *FunctionExpression:
 		for i, param := range node.Parameters {
 			node.Parameters[i], _ = Modify(param, modifier).(*IdentifierExpression)
 		}
+		for name, d := range node.Defaults {
+			node.Defaults[name], _ = Modify(d, modifier).(Expression)
+		}
 		node.Body, _ = Modify(node.Body, modifier).(*BlockStatement)
+	case *InterpolatedStringExpression:
+		for i, part := range node.Parts {
+			node.Parts[i], _ = Modify(part, modifier).(Expression)
+		}
 	case *ArrayLiteralExpression:
 		for i, el := range node.Elements {
 			node.Elements[i], _ = Modify(el, modifier).(Expression)
 		}
 	case *HashLiteralExpression:
-		pairs := make(map[Expression]Expression)
-		for k, v := range node.Pairs {
-			k, _ := Modify(k, modifier).(Expression)
-			v, _ := Modify(v, modifier).(Expression)
-			pairs[k] = v
+		for i, pair := range node.Pairs {
+			node.Pairs[i].Key, _ = Modify(pair.Key, modifier).(Expression)
+			node.Pairs[i].Value, _ = Modify(pair.Value, modifier).(Expression)
 		}
-		node.Pairs = pairs
 	}
 	return modifier(node)
 }
@@ -33,6 +33,44 @@ func TestModify(t *testing.T) {
 			&InfixExpression{Left: one(), Operator: "+", Right: two()},
 			&InfixExpression{Left: two(), Operator: "+", Right: two()},
 		},
+		{
+			&WhileStatement{Condition: one(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: one()}}}},
+			&WhileStatement{Condition: two(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: two()}}}},
+		},
+		{
+			&ForStatement{Iterable: one(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: one()}}}},
+			&ForStatement{Iterable: two(), Body: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: two()}}}},
+		},
+		{
+			&AssignExpression{Target: &IdentifierExpression{Value: "x"}, Operator: "+=", Value: one()},
+			&AssignExpression{Target: &IdentifierExpression{Value: "x"}, Operator: "+=", Value: two()},
+		},
+		{
+			&MatchExpression{Subject: one(), Arms: []*MatchArm{
+				{Pattern: &ArrayPattern{Elements: []Pattern{&LiteralPattern{Value: one()}}}, Guard: one(), Body: one()},
+			}},
+			&MatchExpression{Subject: two(), Arms: []*MatchArm{
+				{Pattern: &ArrayPattern{Elements: []Pattern{&LiteralPattern{Value: two()}}}, Guard: two(), Body: two()},
+			}},
+		},
+		{
+			&IfExpression{Condition: one(), Consequence: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: one()}}}},
+			&IfExpression{Condition: two(), Consequence: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: two()}}}},
+		},
+		{
+			&ThrowStatement{Value: one()},
+			&ThrowStatement{Value: two()},
+		},
+		{
+			&TryExpression{
+				Block:   &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: one()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: one()}}},
+			},
+			&TryExpression{
+				Block:   &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: two()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: two()}}},
+			},
+		},
 		{
 			&PrefixExpression{Operator: "-", Right: one()},
 			&PrefixExpression{Operator: "-", Right: two()},
@@ -41,6 +79,14 @@ func TestModify(t *testing.T) {
 			&IndexExpression{Index: one(), Left: one()},
 			&IndexExpression{Index: two(), Left: two()},
 		},
+		{
+			&MemberExpression{Object: one(), Property: &IdentifierExpression{Value: "x"}},
+			&MemberExpression{Object: two(), Property: &IdentifierExpression{Value: "x"}},
+		},
+		{
+			&SliceExpression{Left: one(), Start: one(), End: nil},
+			&SliceExpression{Left: two(), Start: two(), End: nil},
+		},
 		{
 			&IfExpression{
 				Condition: one(),
@@ -87,6 +133,18 @@ func TestModify(t *testing.T) {
 				Body:       &BlockStatement{Statements: []Statement{&ExpressionStatement{ExpressionValue: two()}}},
 			},
 		},
+		{
+			&FunctionExpression{
+				Parameters: []*IdentifierExpression{{Value: "x"}},
+				Defaults:   map[string]Expression{"x": one()},
+				Body:       &BlockStatement{Statements: []Statement{}},
+			},
+			&FunctionExpression{
+				Parameters: []*IdentifierExpression{{Value: "x"}},
+				Defaults:   map[string]Expression{"x": two()},
+				Body:       &BlockStatement{Statements: []Statement{}},
+			},
+		},
 		{
 			&ArrayLiteralExpression{Elements: []Expression{one(), one()}},
 			&ArrayLiteralExpression{Elements: []Expression{two(), two()}},
@@ -101,19 +159,19 @@ func TestModify(t *testing.T) {
 	}
 
 	hashLiteral := &HashLiteralExpression{
-		Pairs: map[Expression]Expression{
-			one(): one(),
-			one(): one(),
+		Pairs: []HashLiteralPair{
+			{Key: one(), Value: one()},
+			{Key: one(), Value: one()},
 		},
 	}
 	Modify(hashLiteral, turnOneIntoTwo)
 
-	for key, val := range hashLiteral.Pairs {
-		key, _ := key.(*IntegerLiteralExpression)
+	for _, pair := range hashLiteral.Pairs {
+		key, _ := pair.Key.(*IntegerLiteralExpression)
 		if key.Value != 2 {
 			t.Errorf("value is not %d, got=%d", 2, key.Value)
 		}
-		val, _ := val.(*IntegerLiteralExpression)
+		val, _ := pair.Value.(*IntegerLiteralExpression)
 		if val.Value != 2 {
 			t.Errorf("value is not %d, got=%d", 2, val.Value)
 		}
@@ -0,0 +1,92 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ganyariya/go_monkey/token"
+)
+
+/*
+パターン
+`match` の各分岐や分割代入 `let [a, b] = xs;` で、値の形と照合して変数を束縛する
+*/
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+// `_` どんな値にも一致し、何も束縛しない
+type WildcardPattern struct {
+	Token token.Token // token.IDENTIFIER (`_`)
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) Pos() token.Position  { return wp.Token.Pos }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// `x` どんな値にも一致し、その値を x に束縛する
+type BindingPattern struct {
+	Name *IdentifierExpression
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Name.TokenLiteral() }
+func (bp *BindingPattern) Pos() token.Position  { return bp.Name.Pos() }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// `1` `-2.5` `"str"` `true` 値が `==` で等しいときに一致する
+type LiteralPattern struct {
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Value.TokenLiteral() }
+func (lp *LiteralPattern) Pos() token.Position  { return lp.Value.Pos() }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+/*
+`[a, b, ...rest]` 配列の各要素とパターンを照合する
+Rest がなければ要素数が一致する必要がある。Rest があれば残りの要素を配列として束縛する（`..._` は束縛しない）
+*/
+type ArrayPattern struct {
+	Token    token.Token // token.LBRACKET
+	Elements []Pattern
+	Rest     *IdentifierExpression
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) Pos() token.Position  { return ap.Token.Pos }
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, e := range ap.Elements {
+		elements = append(elements, e.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+/*
+`{"type": t}` ハッシュのキーに対応する値とパターンを照合する
+パターンに書かれていないキーがハッシュにあってもよい
+*/
+type HashPattern struct {
+	Token  token.Token // token.LBRACE
+	Keys   []Expression
+	Values []Pattern
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) Pos() token.Position  { return hp.Token.Pos }
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		pairs = append(pairs, fmt.Sprintf("%s:%s", key.String(), hp.Values[i].String()))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
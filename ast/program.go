@@ -1,10 +1,15 @@
 package ast
 
-import "bytes"
+import (
+	"bytes"
+
+	"github.com/ganyariya/go_monkey/token"
+)
 
 // RootNode = 文の集合
 type Program struct {
 	Statements []Statement
+	Comments   []*Comment // 後ろに文が続かない（ファイル末尾の）コメント
 }
 
 func (p *Program) TokenLiteral() string {
@@ -15,6 +20,13 @@ func (p *Program) TokenLiteral() string {
 	}
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+	return token.Position{}
+}
+
 func (p *Program) String() string {
 	var out bytes.Buffer
 	for _, s := range p.Statements {
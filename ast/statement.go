@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/ganyariya/go_monkey/token"
 )
@@ -11,20 +12,32 @@ import (
 type Statement interface {
 	Node
 	statementNode()
+	LeadingComments() []*Comment // 文の直前にあるコメント
+	SetLeadingComments([]*Comment)
 }
 
-// (let x = 5;) Statement
+/*
+(let x = 5;) Statement
+`let [a, b, ...rest] = xs;` `let {"name": n} = h;` のような分割代入では Name の代わりに Pattern を持つ
+*/
 type LetStatement struct {
-	Token token.Token           // token.LET (for トークン)
-	Name  *IdentifierExpression // x (for 識別子（式）)
-	Value Expression            // 5 (for 式)
+	Trivia
+	Token   token.Token           // token.LET (for トークン)
+	Name    *IdentifierExpression // x (for 識別子（式）)
+	Pattern Pattern               // [a, b] (分割代入のときだけ。Name は nil)
+	Value   Expression            // 5 (for 式)
 }
 
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
+func (ls *LetStatement) Pos() token.Position  { return ls.Token.Pos }
 func (ls *LetStatement) statementNode()       {}
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
-	out.WriteString(fmt.Sprintf("%s %s = ", ls.TokenLiteral(), ls.Name.String()))
+	if ls.Pattern != nil {
+		out.WriteString(fmt.Sprintf("%s %s = ", ls.TokenLiteral(), ls.Pattern.String()))
+	} else {
+		out.WriteString(fmt.Sprintf("%s %s = ", ls.TokenLiteral(), ls.Name.String()))
+	}
 	if ls.Value != nil {
 		out.WriteString(ls.Value.String())
 	}
@@ -32,12 +45,32 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+/*
+束縛のドキュメント
+`let` の直上の行に（空行を挟まずに）連続して書かれたコメントの本文を改行で連結したもの
+*/
+func (ls *LetStatement) Doc() string {
+	docs := []string{}
+	line := ls.Token.Pos.Line
+	for i := len(ls.Comments) - 1; i >= 0; i-- {
+		c := ls.Comments[i]
+		if c.Trailing || c.EndLine() != line-1 {
+			break
+		}
+		docs = append([]string{c.Text()}, docs...)
+		line = c.Pos().Line
+	}
+	return strings.Join(docs, "\n")
+}
+
 type ReturnStatement struct {
+	Trivia
 	Token       token.Token // token.RETURN
 	ReturnValue Expression
 }
 
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() token.Position  { return rs.Token.Pos }
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
@@ -51,11 +84,13 @@ func (rs *ReturnStatement) String() string {
 
 // **式だけ**からなる Statement
 type ExpressionStatement struct {
+	Trivia
 	Token           token.Token // 式に含まれる最初のトークン
 	ExpressionValue Expression  // 式
 }
 
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() token.Position  { return es.Token.Pos }
 func (es *ExpressionStatement) statementNode()       {}
 func (es *ExpressionStatement) String() string {
 	if es.ExpressionValue != nil {
@@ -66,12 +101,14 @@ func (es *ExpressionStatement) String() string {
 
 // Block文は複数の文で構成される
 type BlockStatement struct {
+	Trivia
 	Token      token.Token
 	Statements []Statement
 }
 
 func (bs *BlockStatement) statementNode()       {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() token.Position  { return bs.Token.Pos }
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
 	for _, s := range bs.Statements {
@@ -79,3 +116,86 @@ func (bs *BlockStatement) String() string {
 	}
 	return out.String()
 }
+
+/*
+構文エラーのため解析できなかった文
+エディタなどのツールのために、読み飛ばしたトークンの範囲 [Token, To] を保持する
+*/
+type BadStatement struct {
+	Trivia
+	Token token.Token // 文の先頭トークン
+	To    token.Token // 読み飛ばした最後のトークン
+}
+
+func (bs *BadStatement) statementNode()       {}
+func (bs *BadStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BadStatement) Pos() token.Position  { return bs.Token.Pos }
+func (bs *BadStatement) String() string       { return "<bad statement>" }
+
+// while (Condition) { Body }
+type WhileStatement struct {
+	Trivia
+	Token     token.Token // token.WHILE
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) Pos() token.Position  { return ws.Token.Pos }
+func (ws *WhileStatement) String() string {
+	return fmt.Sprintf("while%s %s", ws.Condition.String(), ws.Body.String())
+}
+
+/*
+for (Variable in Iterable) { Body }
+配列は要素、ハッシュはキー、文字列は 1 文字ずつ Variable に束縛して Body を評価する
+*/
+type ForStatement struct {
+	Trivia
+	Token    token.Token // token.FOR
+	Variable *IdentifierExpression
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) Pos() token.Position  { return fs.Token.Pos }
+func (fs *ForStatement) String() string {
+	return fmt.Sprintf("for (%s in %s) %s", fs.Variable.String(), fs.Iterable.String(), fs.Body.String())
+}
+
+// break; （最も内側のループを抜ける）
+type BreakStatement struct {
+	Trivia
+	Token token.Token // token.BREAK
+}
+
+func (bs *BreakStatement) statementNode()       {}
+func (bs *BreakStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BreakStatement) Pos() token.Position  { return bs.Token.Pos }
+func (bs *BreakStatement) String() string       { return "break;" }
+
+// continue; （最も内側のループの次の繰り返しに進む）
+type ContinueStatement struct {
+	Trivia
+	Token token.Token // token.CONTINUE
+}
+
+func (cs *ContinueStatement) statementNode()       {}
+func (cs *ContinueStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ContinueStatement) Pos() token.Position  { return cs.Token.Pos }
+func (cs *ContinueStatement) String() string       { return "continue;" }
+
+// throw Value; （Value を投げて最も内側の try の catch に制御を移す）
+type ThrowStatement struct {
+	Trivia
+	Token token.Token // token.THROW
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) Pos() token.Position  { return ts.Token.Pos }
+func (ts *ThrowStatement) String() string       { return fmt.Sprintf("throw %s;", ts.Value.String()) }
@@ -0,0 +1,127 @@
+package evaluator
+
+import (
+	"math/big"
+
+	"github.com/ganyariya/go_monkey/object"
+)
+
+/*
+整数演算が int64 に収まらないときは object.BigInt に昇格して計算する
+巨大なべき乗やシフトでホストのメモリや CPU を使い果たさないように、結果のビット数に上限を設ける
+*/
+const maxBigIntBits = 1 << 16
+
+func isInteger(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.BIGINT_OBJ
+}
+
+func toBigInt(obj object.Object) (*big.Int, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value), true
+	case *object.BigInt:
+		return obj.Value, true
+	default:
+		return nil, false
+	}
+}
+
+// 上限を超えていなければ Integer（int64 に収まる場合）か BigInt を返す
+func newBigIntegerObject(value *big.Int) object.Object {
+	if value.BitLen() > maxBigIntBits {
+		return newKindError(object.OVERFLOW_ERROR, "integer overflow: result exceeds %d bits", maxBigIntBits)
+	}
+	return object.NewInteger(value)
+}
+
+// Integer と BigInt の中置演算。割り算と剰余は Integer と同じく 0 方向に切り捨てる
+func evalBigIntInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue, _ := toBigInt(left)
+	rightValue, _ := toBigInt(right)
+	switch operator {
+	case "+":
+		return newBigIntegerObject(new(big.Int).Add(leftValue, rightValue))
+	case "-":
+		return newBigIntegerObject(new(big.Int).Sub(leftValue, rightValue))
+	case "*":
+		if leftValue.BitLen()+rightValue.BitLen() > maxBigIntBits+1 {
+			return newKindError(object.OVERFLOW_ERROR, "integer overflow: result exceeds %d bits", maxBigIntBits)
+		}
+		return newBigIntegerObject(new(big.Int).Mul(leftValue, rightValue))
+	case "/":
+		if rightValue.Sign() == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %s / %s", left.Inspect(), right.Inspect())
+		}
+		return newBigIntegerObject(new(big.Int).Quo(leftValue, rightValue))
+	case "%":
+		if rightValue.Sign() == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %s %% %s", left.Inspect(), right.Inspect())
+		}
+		return newBigIntegerObject(new(big.Int).Rem(leftValue, rightValue))
+	case "**":
+		return evalBigIntPower(left, right)
+	case "&":
+		return newBigIntegerObject(new(big.Int).And(leftValue, rightValue))
+	case "|":
+		return newBigIntegerObject(new(big.Int).Or(leftValue, rightValue))
+	case "^":
+		return newBigIntegerObject(new(big.Int).Xor(leftValue, rightValue))
+	case "<<", ">>":
+		if rightValue.Sign() < 0 {
+			return newKindError(object.VALUE_ERROR, "negative shift count: %s %s %s", left.Inspect(), operator, right.Inspect())
+		}
+		if operator == ">>" {
+			if !rightValue.IsInt64() || rightValue.Int64() > int64(leftValue.BitLen()) {
+				return newBigIntegerObject(new(big.Int).Rsh(leftValue, uint(leftValue.BitLen()+1)))
+			}
+			return newBigIntegerObject(new(big.Int).Rsh(leftValue, uint(rightValue.Int64())))
+		}
+		if !rightValue.IsInt64() || int64(leftValue.BitLen())+rightValue.Int64() > maxBigIntBits {
+			return newKindError(object.OVERFLOW_ERROR, "integer overflow: result exceeds %d bits", maxBigIntBits)
+		}
+		return newBigIntegerObject(new(big.Int).Lsh(leftValue, uint(rightValue.Int64())))
+	case "==":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) != 0)
+	case "<":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) >= 0)
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+/*
+整数のべき乗（`**` と pow から使う）
+負の指数は Float になる（`2 ** -1 == 0.5`）。int64 に収まらなければ BigInt になる
+*/
+func evalIntegerPower(left, right object.Object) object.Object {
+	if l, ok := left.(*object.Integer); ok {
+		if r, ok := right.(*object.Integer); ok && r.Value >= 0 {
+			if value, ok := intPow(l.Value, r.Value); ok {
+				return &object.Integer{Value: value}
+			}
+		}
+	}
+	return evalBigIntPower(left, right)
+}
+
+func evalBigIntPower(left, right object.Object) object.Object {
+	base, _ := toBigInt(left)
+	exp, _ := toBigInt(right)
+	if exp.Sign() < 0 {
+		return evalFloatInfixExpression("**", left, right)
+	}
+	// 0, 1, -1 以外の底は指数に比例してビット数が増える
+	if base.CmpAbs(big.NewInt(1)) > 0 && (!exp.IsInt64() || exp.Int64() > maxBigIntBits/int64(base.BitLen()-1)) {
+		return newKindError(object.OVERFLOW_ERROR, "integer overflow: %s ** %s exceeds %d bits", left.Inspect(), right.Inspect(), maxBigIntBits)
+	}
+	return newBigIntegerObject(new(big.Int).Exp(base, exp, nil))
+}
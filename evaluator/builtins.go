@@ -12,11 +12,11 @@ func builtinLen(args ...object.Object) object.Object {
 	}
 	switch arg := args[0].(type) {
 	case *object.String:
-		return &object.Integer{Value: int64(len(arg.Value))}
+		return &object.Integer{Value: int64(arg.Len())}
 	case *object.Array:
 		return &object.Integer{Value: int64(len(arg.Elements))}
 	default:
-		return newError("argument to `len` not supported, got=%s", arg.Type())
+		return newKindError(object.TYPE_ERROR, "argument to `len` not supported, got=%s", arg.Type())
 	}
 }
 
@@ -81,6 +81,54 @@ func builtinPush(args ...object.Object) object.Object {
 	return &object.Array{Elements: newElements}
 }
 
+/*
+delete(h, k) は h からキー k をその場で取り除き、取り除いた値を返す（k がなければ NULL）
+代入 `h[k] = v` と同様に、同じハッシュを参照している変数やクロージャからも削除が見える
+*/
+func builtinDelete(args ...object.Object) object.Object {
+	if ret := checkArgsLen(2, args...); ret != nil {
+		return ret
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "argument to `delete` must be HASH, got %s", args[0].Type())
+	}
+	key, ok := args[1].(object.Hashable)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "unusable as hash key: %s", args[1].Type())
+	}
+	pair, ok := hash.Get(key.HashKey())
+	if !ok {
+		return NULL
+	}
+	hash.Delete(key.HashKey())
+	return pair.Value
+}
+
+// keys(h) / values(h) はキー・値を挿入順に並べた新しい配列を返す
+func builtinKeys(args ...object.Object) object.Object {
+	return collectHashPairs("keys", func(pair object.HashPair) object.Object { return pair.Key }, args...)
+}
+
+func builtinValues(args ...object.Object) object.Object {
+	return collectHashPairs("values", func(pair object.HashPair) object.Object { return pair.Value }, args...)
+}
+
+func collectHashPairs(name string, pick func(object.HashPair) object.Object, args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	hash, ok := args[0].(*object.Hash)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "argument to `%s` must be HASH, got %s", name, args[0].Type())
+	}
+	elements := []object.Object{}
+	for _, pair := range hash.Pairs() {
+		elements = append(elements, pick(pair))
+	}
+	return &object.Array{Elements: elements}
+}
+
 func builtinPuts(args ...object.Object) object.Object {
 	for _, arg := range args {
 		fmt.Println(arg.Inspect())
@@ -89,12 +137,34 @@ func builtinPuts(args ...object.Object) object.Object {
 }
 
 var builtins = map[string]*object.Builtin{
-	"len":   {Fn: builtinLen},
-	"first": {Fn: builtinFirst},
-	"last":  {Fn: builtinLast},
-	"rest":  {Fn: builtinRest},
-	"push":  {Fn: builtinPush},
-	"puts":  {Fn: builtinPuts},
+	"len":    {Fn: builtinLen, Params: []string{"x"}},
+	"first":  {Fn: builtinFirst, Params: []string{"arr"}},
+	"last":   {Fn: builtinLast, Params: []string{"arr"}},
+	"rest":   {Fn: builtinRest, Params: []string{"arr"}},
+	"push":   {Fn: builtinPush, Params: []string{"arr", "value"}},
+	"delete": {Fn: builtinDelete, Params: []string{"hash", "key"}},
+	"keys":   {Fn: builtinKeys, Params: []string{"hash"}},
+	"values": {Fn: builtinValues, Params: []string{"hash"}},
+	"puts":   {Fn: builtinPuts, Rest: "args"},
+	"abs":    {Fn: builtinAbs, Params: []string{"x"}},
+	"floor":  {Fn: builtinFloor, Params: []string{"x"}},
+	"ceil":   {Fn: builtinCeil, Params: []string{"x"}},
+	"round":  {Fn: builtinRound, Params: []string{"x"}},
+	"sqrt":   {Fn: builtinSqrt, Params: []string{"x"}},
+	"pow":    {Fn: builtinPow, Params: []string{"base", "exp"}},
+	"min":    {Fn: builtinMin, Rest: "args"},
+	"max":    {Fn: builtinMax, Rest: "args"},
+	"int":    {Fn: builtinInt, Params: []string{"x"}},
+	"float":  {Fn: builtinFloat, Params: []string{"x"}},
+
+	"type":        {Fn: builtinType, Params: []string{"x"}},
+	"arity":       {Fn: builtinArity, Params: []string{"fn"}},
+	"params":      {Fn: builtinParams, Params: []string{"fn"}},
+	"is_callable": {Fn: typePredicate(isCallable), Params: []string{"x"}},
+	"is_hash":     {Fn: typePredicate(isObjectType(object.HASH_OBJ)), Params: []string{"x"}},
+	"is_array":    {Fn: typePredicate(isObjectType(object.ARRAY_OBJ)), Params: []string{"x"}},
+	"is_string":   {Fn: typePredicate(isObjectType(object.STRING_OBJ)), Params: []string{"x"}},
+	"is_null":     {Fn: typePredicate(isObjectType(object.NULL_OBJ)), Params: []string{"x"}},
 }
 
 // ------------------------------------------------------------------------------------
@@ -102,14 +172,14 @@ var builtins = map[string]*object.Builtin{
 
 func checkArgsLen(length int, args ...object.Object) *object.Error {
 	if len(args) != length {
-		return newError("wrong number of arguments. expected=%d, got=%d", length, len(args))
+		return newKindError(object.ARGUMENT_ERROR, "wrong number of arguments. expected=%d, got=%d", length, len(args))
 	}
 	return nil
 }
 func checkArgsIsArray(name string, args ...object.Object) (*object.Error, *object.Array) {
 	arr, ok := args[0].(*object.Array)
 	if !ok {
-		return newError("argument to `%s` must be ARRAY, got=%T", name, args[0]), nil
+		return newKindError(object.TYPE_ERROR, "argument to `%s` must be ARRAY, got=%T", name, args[0]), nil
 	}
 	return nil, arr
 }
@@ -0,0 +1,188 @@
+package evaluator
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ganyariya/go_monkey/object"
+)
+
+/*
+数値を扱う組み込み関数
+Integer と Float の両方を受け取り、Float が混ざれば Float を返す
+*/
+
+func builtinAbs(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	switch arg := args[0].(type) {
+	case *object.Integer:
+		if arg.Value < 0 {
+			return &object.Integer{Value: -arg.Value}
+		}
+		if arg.Value == math.MinInt64 {
+			return object.NewInteger(new(big.Int).Abs(big.NewInt(arg.Value)))
+		}
+		return arg
+	case *object.BigInt:
+		return object.NewInteger(new(big.Int).Abs(arg.Value))
+	case *object.Float:
+		return &object.Float{Value: math.Abs(arg.Value)}
+	default:
+		return newKindError(object.TYPE_ERROR, "argument to `abs` must be INTEGER or FLOAT, got=%s", arg.Type())
+	}
+}
+
+func builtinFloor(args ...object.Object) object.Object {
+	return applyRounding("floor", math.Floor, args...)
+}
+
+func builtinCeil(args ...object.Object) object.Object {
+	return applyRounding("ceil", math.Ceil, args...)
+}
+
+func builtinRound(args ...object.Object) object.Object {
+	return applyRounding("round", math.Round, args...)
+}
+
+// Integer はそのまま返し、Float は fn で丸めた Float を返す
+func applyRounding(name string, fn func(float64) float64, args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.BigInt:
+		return arg
+	case *object.Float:
+		return &object.Float{Value: fn(arg.Value)}
+	default:
+		return newKindError(object.TYPE_ERROR, "argument to `%s` must be INTEGER or FLOAT, got=%s", name, arg.Type())
+	}
+}
+
+func builtinSqrt(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	value, ok := toFloat(args[0])
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "argument to `sqrt` must be INTEGER or FLOAT, got=%s", args[0].Type())
+	}
+	return &object.Float{Value: math.Sqrt(value)}
+}
+
+// 整数の非負整数乗は Integer（int64 に収まらなければ BigInt）、それ以外は Float を返す
+func builtinPow(args ...object.Object) object.Object {
+	if ret := checkArgsLen(2, args...); ret != nil {
+		return ret
+	}
+	base, ok1 := toFloat(args[0])
+	exp, ok2 := toFloat(args[1])
+	if !ok1 || !ok2 {
+		return newKindError(object.TYPE_ERROR, "arguments to `pow` must be INTEGER or FLOAT, got=%s, %s", args[0].Type(), args[1].Type())
+	}
+	if isInteger(args[0]) && isInteger(args[1]) {
+		return evalIntegerPower(args[0], args[1])
+	}
+	return &object.Float{Value: math.Pow(base, exp)}
+}
+
+// 繰り返し二乗法（int64 に収まらなければ false を返す）
+func intPow(base, exp int64) (int64, bool) {
+	result := int64(1)
+	for exp > 0 {
+		if exp&1 == 1 {
+			r, ok := mulInt64(result, base)
+			if !ok {
+				return 0, false
+			}
+			result = r
+		}
+		exp >>= 1
+		if exp > 0 {
+			b, ok := mulInt64(base, base)
+			if !ok {
+				return 0, false
+			}
+			base = b
+		}
+	}
+	return result, true
+}
+
+func builtinMin(args ...object.Object) object.Object {
+	return selectNumber("min", func(a, b float64) bool { return a < b }, args...)
+}
+
+func builtinMax(args ...object.Object) object.Object {
+	return selectNumber("max", func(a, b float64) bool { return a > b }, args...)
+}
+
+// better(候補, 現在の値) が true になる引数を選んでそのまま返す（型は選ばれた引数のまま）
+func selectNumber(name string, better func(a, b float64) bool, args ...object.Object) object.Object {
+	if len(args) == 0 {
+		return newKindError(object.ARGUMENT_ERROR, "wrong number of arguments. expected at least 1, got=0")
+	}
+	var selected object.Object
+	var selectedValue float64
+	for _, arg := range args {
+		value, ok := toFloat(arg)
+		if !ok {
+			return newKindError(object.TYPE_ERROR, "arguments to `%s` must be INTEGER or FLOAT, got=%s", name, arg.Type())
+		}
+		if selected == nil || better(value, selectedValue) {
+			selected, selectedValue = arg, value
+		}
+	}
+	return selected
+}
+
+// Float は 0 方向に切り捨て、String は整数リテラル（0x などの接頭辞も可）として解釈する
+// int64 に収まらない値は BigInt になる
+func builtinInt(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.BigInt:
+		return arg
+	case *object.Float:
+		if math.IsNaN(arg.Value) || math.IsInf(arg.Value, 0) {
+			return newKindError(object.VALUE_ERROR, "cannot convert %s to INTEGER", arg.Inspect())
+		}
+		value, _ := big.NewFloat(arg.Value).Int(nil)
+		return newBigIntegerObject(value)
+	case *object.String:
+		value, ok := new(big.Int).SetString(strings.TrimSpace(arg.Value), 0)
+		if !ok {
+			return newKindError(object.VALUE_ERROR, "cannot convert %q to INTEGER", arg.Value)
+		}
+		return newBigIntegerObject(value)
+	default:
+		return newKindError(object.TYPE_ERROR, "argument to `int` not supported, got=%s", arg.Type())
+	}
+}
+
+func builtinFloat(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	switch arg := args[0].(type) {
+	case *object.Integer, *object.BigInt:
+		value, _ := toFloat(arg)
+		return &object.Float{Value: value}
+	case *object.Float:
+		return arg
+	case *object.String:
+		value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+		if err != nil {
+			return newKindError(object.VALUE_ERROR, "cannot convert %q to FLOAT", arg.Value)
+		}
+		return &object.Float{Value: value}
+	default:
+		return newKindError(object.TYPE_ERROR, "argument to `float` not supported, got=%s", arg.Type())
+	}
+}
@@ -0,0 +1,71 @@
+package evaluator
+
+import (
+	"github.com/ganyariya/go_monkey/object"
+)
+
+// type(x) は x の ObjectType を文字列で返す（type(1) == "INTEGER"）
+func builtinType(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	return &object.String{Value: string(args[0].Type())}
+}
+
+// arity(f) は可変長引数を除いた仮引数の数を返す（デフォルト値のある仮引数も数える）
+func builtinArity(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	fn, errObj := checkArgIsCallable("arity", args[0])
+	if errObj != nil {
+		return errObj
+	}
+	return &object.Integer{Value: int64(fn.Arity())}
+}
+
+// params(f) は仮引数名を文字列の配列で返す（可変長引数は "...rest"）
+func builtinParams(args ...object.Object) object.Object {
+	if ret := checkArgsLen(1, args...); ret != nil {
+		return ret
+	}
+	fn, errObj := checkArgIsCallable("params", args[0])
+	if errObj != nil {
+		return errObj
+	}
+	elements := []object.Object{}
+	for _, name := range fn.ParameterNames() {
+		elements = append(elements, &object.String{Value: name})
+	}
+	return &object.Array{Elements: elements}
+}
+
+/*
+is_callable(x) や is_hash(x) のような型を判定する組み込み関数をつくる
+Function / Builtin / Macro はどれも Callable として扱う
+*/
+func typePredicate(match func(object.Object) bool) object.BuiltinFunction {
+	return func(args ...object.Object) object.Object {
+		if ret := checkArgsLen(1, args...); ret != nil {
+			return ret
+		}
+		return nativeBoolToBooleanObject(match(args[0]))
+	}
+}
+
+func isCallable(obj object.Object) bool {
+	_, ok := obj.(object.Callable)
+	return ok
+}
+
+func isObjectType(t object.ObjectType) func(object.Object) bool {
+	return func(obj object.Object) bool { return obj.Type() == t }
+}
+
+func checkArgIsCallable(name string, arg object.Object) (object.Callable, *object.Error) {
+	fn, ok := arg.(object.Callable)
+	if !ok {
+		return nil, newKindError(object.TYPE_ERROR, "argument to `%s` must be callable, got=%s", name, arg.Type())
+	}
+	return fn, nil
+}
@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/object"
+)
+
+// 比較中の (左, 右) の組。自分自身を含む配列・ハッシュの比較が終わらなくなるのを防ぐ
+type comparingPair struct {
+	left, right object.Object
+}
+
+/*
+`==` で使う構造的な等価性
+- 数値は型をまたいで値で比較する（1 == 1.0）
+- 配列は要素を順に、ハッシュはすべてのキーと値を比較する
+- Quote は AST の構造で比較する（位置は無視する）
+- 関数などそれ以外のオブジェクトは同じオブジェクトのときだけ等しい
+*/
+func objectsEqual(left, right object.Object) bool {
+	return equalObjects(left, right, map[comparingPair]bool{})
+}
+
+func equalObjects(left, right object.Object, comparing map[comparingPair]bool) bool {
+	if left == right {
+		return true
+	}
+	if isNumber(left) && isNumber(right) {
+		return evalInfixOperator("==", left, right) == TRUE
+	}
+	if left.Type() != right.Type() {
+		return false
+	}
+	// 比較中の組に再び出会ったら循環しているので、ここでは等しいとみなして残りの要素で判断する
+	pair := comparingPair{left, right}
+	if comparing[pair] {
+		return true
+	}
+	comparing[pair] = true
+	defer delete(comparing, pair)
+
+	switch left := left.(type) {
+	case *object.String:
+		return left.Value == right.(*object.String).Value
+	case *object.Boolean:
+		return left.Value == right.(*object.Boolean).Value
+	case *object.Null:
+		return true
+	case *object.Quote:
+		return ast.Equal(left.Node, right.(*object.Quote).Node)
+	case *object.Array:
+		return equalArrays(left, right.(*object.Array), comparing)
+	case *object.Hash:
+		return equalHashes(left, right.(*object.Hash), comparing)
+	}
+	return false
+}
+
+func equalArrays(left, right *object.Array, comparing map[comparingPair]bool) bool {
+	if len(left.Elements) != len(right.Elements) {
+		return false
+	}
+	for i := range left.Elements {
+		if !equalObjects(left.Elements[i], right.Elements[i], comparing) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalHashes(left, right *object.Hash, comparing map[comparingPair]bool) bool {
+	if left.Len() != right.Len() {
+		return false
+	}
+	// 挿入順は比較しない（{"a": 1, "b": 2} == {"b": 2, "a": 1}）
+	for _, pair := range left.Pairs() {
+		other, ok := right.Get(pair.Key.(object.Hashable).HashKey())
+		if !ok || !equalObjects(pair.Value, other.Value, comparing) {
+			return false
+		}
+	}
+	return true
+}
@@ -9,6 +9,15 @@ import (
 AST Node を再帰的に評価して Object System の Object に変換する
 */
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	obj := evalNode(node, env)
+	// エラーが最初に通過したノード（= エラーの原因）の位置を記録する
+	if errObj, ok := obj.(*object.Error); ok && !errObj.Pos.IsValid() {
+		errObj.Pos = node.Pos()
+	}
+	return obj
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
@@ -18,32 +27,63 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalReturnStatement(node, env)
 	case *ast.LetStatement:
 		return evalLetStatement(node, env)
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
 	case *ast.ExpressionStatement:
 		return Eval(node.ExpressionValue, env)
 	case *ast.IntegerLiteralExpression:
 		return evalIntegerLiteralExpression(node)
+	case *ast.FloatLiteralExpression:
+		return evalFloatLiteralExpression(node)
 	case *ast.BooleanExpression:
 		return evalBooleanExpression(node)
+	case *ast.NullLiteralExpression:
+		return NULL
 	case *ast.IdentifierExpression:
 		return evalIdentifierExpression(node, env)
 	case *ast.StringLiteralExpression:
 		return evalStringLiteralExpression(node)
+	case *ast.InterpolatedStringExpression:
+		return evalInterpolatedStringExpression(node, env)
 	case *ast.PrefixExpression:
 		return evalPrefixExpression(node, env)
 	case *ast.InfixExpression:
 		return evalInfixExpression(node, env)
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 	case *ast.FunctionExpression:
 		return evalFunctionExpression(node, env)
+	case *ast.MacroExpression:
+		// 実行時のマクロは呼び出せないが、値として arity() などで調べられる
+		return &object.Macro{Parameters: node.Parameters, Body: node.Body, Env: env}
 	case *ast.CallExpression:
 		return evalCallExpression(node, env)
 	case *ast.ArrayLiteralExpression:
 		return evalArrayLiteralExpression(node, env)
 	case *ast.IndexExpression:
 		return evalIndexExpression(node, env)
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
 	case *ast.HashLiteralExpression:
 		return evalHashLiteralexpression(node, env)
+	case *ast.BadStatement, *ast.BadExpression:
+		return newKindError(object.TYPE_ERROR, "cannot evaluate %s", node.String())
 	}
 	return nil
 }
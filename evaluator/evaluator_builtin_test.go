@@ -15,18 +15,45 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`len("");`, 0},
 		{`len("four");`, 4},
 		{`len("hello, world");`, 12},
+		{`len("こんにちは");`, 5},
 		{`len(1);`, "argument to `len` not supported, got=INTEGER"},
 		{`len("one", "two");`, "wrong number of arguments. expected=1, got=2"},
 		{`len([]);`, 0},
 		{`len([1, "hello"]);`, 2},
 		{`first([10, 20]);`, 10},
 		{`last([10, 20]);`, 20},
+		{`abs(-3)`, 3},
+		{`abs(-2.5)`, 2.5},
+		{`floor(2.7)`, 2.0},
+		{`ceil(2.1)`, 3.0},
+		{`round(2.5)`, 3.0},
+		{`floor(4)`, 4},
+		{`sqrt(16)`, 4.0},
+		{`pow(2, 10)`, 1024},
+		{`pow(2, -1)`, 0.5},
+		{`pow(2.0, 3)`, 8.0},
+		{`min(3, 1.5, 2)`, 1.5},
+		{`max(3, 1.5, 2)`, 3},
+		{`int(3.9)`, 3},
+		{`int(-3.9)`, -3},
+		{`int("0xff")`, 255},
+		{`float(3)`, 3.0},
+		{`float("2.5")`, 2.5},
+		{`sqrt("a")`, "argument to `sqrt` must be INTEGER or FLOAT, got=STRING"},
+		{`int("abc")`, "cannot convert \"abc\" to INTEGER"},
+		{`min()`, "wrong number of arguments. expected at least 1, got=0"},
+		{`int(sqrt(-1))`, "cannot convert NaN to INTEGER"},
+		{`let h = {"a": 1, "b": 2}; delete(h, "a")`, 1},
+		{`delete([1], 0)`, "argument to `delete` must be HASH, got ARRAY"},
+		{`delete({}, [1])`, "unusable as hash key: ARRAY"},
 	}
 	for _, tt := range tests {
 		evaluated := callEval(tt.input)
 		switch expected := tt.expected.(type) {
 		case int:
 			checkIntegerObject(t, evaluated, int64(expected), tt.input)
+		case float64:
+			checkFloatObject(t, evaluated, expected, tt.input)
 		case string:
 			errObj, ok := evaluated.(*object.Error)
 			if !ok {
@@ -36,3 +63,51 @@ func TestBuiltinFunctions(t *testing.T) {
 		}
 	}
 }
+
+func TestReflectionBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"null", "null"},
+		{"let x = null; x == null", "true"},
+		{"if (null) { 1 } else { 2 }", "2"},
+		{"match (first([])) { null => \"empty\", _ => \"some\" }", "empty"},
+		{"type(1)", "INTEGER"},
+		{"type(1.5)", "FLOAT"},
+		{`type("a")`, "STRING"},
+		{"type(null)", "NULL"},
+		{"type([])", "ARRAY"},
+		{"type({})", "HASH"},
+		{"type(fn() {})", "FUNCTION"},
+		{"type(len)", "BUILTIN"},
+		{"type(macro(x) { x })", "MACRO"},
+		{"arity(fn(a, b) {})", "2"},
+		{"arity(fn(a, b = 1, ...rest) {})", "2"},
+		{"arity(len)", "1"},
+		{"arity(puts)", "0"},
+		{"arity(macro(a, b, c) { a })", "3"},
+		{"params(fn(a, b = 1, ...rest) {})", "[a, b, ...rest]"},
+		{"params(pow)", "[base, exp]"},
+		{"params(max)", "[...args]"},
+		{"params(macro(x) { x })", "[x]"},
+		{"params((a, b) => a + b)", "[a, b]"},
+		{"is_callable(fn() {})", "true"},
+		{"is_callable(len)", "true"},
+		{"is_callable(macro() { 1 })", "true"},
+		{"is_callable(1)", "false"},
+		{"is_hash({})", "true"},
+		{"is_hash([])", "false"},
+		{"is_array([])", "true"},
+		{`is_string("")`, "true"},
+		{"is_null(null)", "true"},
+		{"is_null(0)", "false"},
+		{"arity(1)", "ERROR: 1:6: argument to `arity` must be callable, got=INTEGER"},
+		{`params("f")`, "ERROR: 1:7: argument to `params` must be callable, got=STRING"},
+		{"type()", "ERROR: 1:5: wrong number of arguments. expected=1, got=0"},
+	}
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
@@ -2,11 +2,14 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/ganyariya/go_monkey/lexer"
 	"github.com/ganyariya/go_monkey/object"
 	"github.com/ganyariya/go_monkey/parser"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEvalIntegerExpression(t *testing.T) {
@@ -30,6 +33,31 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"3 * 3 * 3 + 10", 37},
 		{"3 * (3 * 3) + 10", 37},
 		{"3 * (3 * 3 + 10)", 57},
+		{"0xFF", 255},
+		{"0o17", 15},
+		{"0b1010", 10},
+		{"1_000_000", 1000000},
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"0xF0 & 0x3C", 0x30},
+		{"0xF0 | 0x0F", 0xFF},
+		{"0xFF ^ 0x0F", 0xF0},
+		{"~0", -1},
+		{"1 << 10", 1024},
+		{"-16 >> 2", -4},
+		{"1 << 62", 1 << 62},
+		{"-1 << 63", -1 << 63},
+		{"0 << 100", 0},
+		{"1 >> 64", 0},
+		{"0b1 << 3 | 0b1", 9},
+		{"2 ** 10", 1024},
+		{"2 ** 3 ** 2", 512},
+		{"-2 ** 2", -4},
+		{"(-2) ** 3", -8},
+		{"3 ** 0", 1},
+		{"2 * 3 ** 2", 18},
+		{"2 ** 62 + (2 ** 62 - 1)", 9223372036854775807},
+		{"(-2) ** 63", -9223372036854775808},
 	}
 	for _, tt := range tests {
 		evaluated := callEval(tt.input)
@@ -37,6 +65,30 @@ func TestEvalIntegerExpression(t *testing.T) {
 	}
 }
 
+func TestEvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14", 3.14},
+		{"-2.5", -2.5},
+		{"1e-9", 1e-9},
+		{"1.5 + 1.5", 3.0},
+		{"1 + 0.5", 1.5},
+		{"0.5 * 4", 2.0},
+		{"7 / 2.0", 3.5},
+		{"10 - 2.5 * 2", 5.0},
+		{"7.5 % 2", 1.5},
+		{"2 ** -1", 0.5},
+		{"4 ** 0.5", 2.0},
+		{"1.5 ** 2", 2.25},
+	}
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		checkFloatObject(t, evaluated, tt.expected, tt.input)
+	}
+}
+
 func TestEvalBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -61,6 +113,13 @@ func TestBangOperator(t *testing.T) {
 		{"!!false", false},
 		{"!!5", true},
 		{"!!0", false},
+		{"!0.0", true},
+		{"1 == 1.0", true},
+		{"1 != 1.0", false},
+		{"1 < 1.5", true},
+		{"2.5 > 3", false},
+		{"0.1 + 0.2 == 0.3", false},
+		{"1e308 * 10 > 1e308", true},
 		{"1 == 1", true},
 		{"1 != 1", false},
 		{"1 == 2", false},
@@ -81,6 +140,20 @@ func TestBangOperator(t *testing.T) {
 		{`"Hello" != "World"`, true},
 		{`!"World"`, false},
 		{`!""`, true},
+		{"1 <= 1", true},
+		{"2 <= 1", false},
+		{"1 >= 1", true},
+		{"1 >= 2", false},
+		{"1 <= 1.5", true},
+		{"1.5 >= 2", false},
+		{`"a" < "b"`, true},
+		{`"b" > "a"`, true},
+		{`"abc" < "abd"`, true},
+		{`"ab" < "abc"`, true},
+		{`"B" < "a"`, true},
+		{`"a" <= "a"`, true},
+		{`"b" >= "c"`, false},
+		{`"あ" > "z"`, true},
 	}
 	for _, tt := range tests {
 		evaluated := callEval(tt.input)
@@ -157,6 +230,26 @@ func TestErrorHandling(t *testing.T) {
 		{"foobar;", "identifier not found: foobar"},
 		{"let x = 10 + foobar;", "identifier not found: foobar"},
 		{`"Hello" - "World"`, "unknown operator: STRING - STRING"},
+		{"5 % 0", "division by zero: 5 % 0"},
+		{"1 << -1", "negative shift count: 1 << -1"},
+		{"1.5 & 1", "unknown operator: FLOAT & INTEGER"},
+		{"~true", "unknown operator: ~BOOLEAN"},
+		{"1 / 0", "division by zero: 1 / 0"},
+		{"let x = 0; 10 / x", "division by zero: 10 / 0"},
+		{"(2 ** 64) / 0", "division by zero: 18446744073709551616 / 0"},
+		{"(2 ** 64) % 0", "division by zero: 18446744073709551616 % 0"},
+		{"1.0 / 0", "division by zero: 1.0 / 0"},
+		{"1.0 % 0", "division by zero: 1.0 % 0"},
+		{"0.0 / 0", "division by zero: 0.0 / 0"},
+		{"1 / 0.0", "division by zero: 1 / 0.0"},
+		{"-2.5 % -0.0", "division by zero: -2.5 % -0.0"},
+		{"(2 ** 64) / 0.0", "division by zero: 18446744073709551616 / 0.0"},
+		{"2 ** 100000", "integer overflow: 2 ** 100000 exceeds 65536 bits"},
+		{"(2 ** 64) << 70000", "integer overflow: result exceeds 65536 bits"},
+		{"1 << 70000", "integer overflow: result exceeds 65536 bits"},
+		{"(2 ** 64) << -1", "negative shift count: 18446744073709551616 << -1"},
+		{`"a" ** "b"`, "unknown operator: STRING ** STRING"},
+		{`"a" <= 1`, "type mismatch: STRING <= INTEGER"},
 	}
 
 	for _, tt := range tests {
@@ -172,6 +265,29 @@ func TestErrorHandling(t *testing.T) {
 	}
 }
 
+func TestErrorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1;\nlet y = x + z;", "ERROR: 2:13: identifier not found: z"},
+		{"let f = fn(a) {\n  a + true\n};\nf(1);", "ERROR: 2:5: type mismatch: INTEGER + BOOLEAN"},
+		{"len(1, 2)", "ERROR: 1:4: wrong number of arguments. expected=1, got=2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("Error object is not returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Inspect() != tt.expected {
+			t.Errorf("Wrong error. expected=%s, got=%s", tt.expected, errObj.Inspect())
+		}
+	}
+}
+
 func TestLetStatements(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -250,6 +366,38 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
+func TestFunctionParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let add = fn(x, y = 2) { x + y }; add(1);", "3"},
+		{"let add = fn(x, y = 2) { x + y }; add(1, 10);", "11"},
+		{"let f = fn(x, y = x * 2) { [x, y] }; f(3);", "[3, 6]"},
+		{"let n = 1; let f = fn(x = n) { x }; let n = 5; f();", "5"},
+		{"let f = fn(first, ...rest) { [first, rest] }; f(1, 2, 3);", "[1, [2, 3]]"},
+		{"let f = fn(first, ...rest) { rest }; f(1);", "[]"},
+		{"let f = fn(...args) { len(args) }; f();", "0"},
+		{"let f = fn(a, b = 2, ...rest) { [a, b, rest] }; f(1);", "[1, 2, []]"},
+		{"let f = fn(a, b = 2, ...rest) { [a, b, rest] }; f(1, 3, 5, 7);", "[1, 3, [5, 7]]"},
+		{"let sum = fn(...xs) { let total = 0; for (x in xs) { total += x } total }; sum(1, 2, 3, 4);", "10"},
+		{"let add = fn(x, y = 2) { x + y }; add", "fn(x, y = 2) {\n(x + y)\n}"},
+		// 引数の数の誤りはエラーになる
+		{"let add = fn(a, b) { a + b }; add(1);", "ERROR: 1:34: wrong number of arguments to `add`. expected=2, got=1"},
+		{"let add = fn(a, b) { a + b }; add(1, 2, 3);", "ERROR: 1:34: wrong number of arguments to `add`. expected=2, got=3"},
+		{"fn(a) { a }();", "ERROR: 1:12: wrong number of arguments to anonymous function. expected=1, got=0"},
+		{"let f = fn(a, b = 1) { a }; f();", "ERROR: 1:30: wrong number of arguments to `f`. expected 1 to 2, got=0"},
+		{"let f = fn(a, b = 1) { a }; f(1, 2, 3);", "ERROR: 1:30: wrong number of arguments to `f`. expected 1 to 2, got=3"},
+		{"let f = fn(a, ...rest) { a }; f();", "ERROR: 1:32: wrong number of arguments to `f`. expected at least 1, got=0"},
+		{"let f = fn(a = b) { a }; f();", "ERROR: 1:16: identifier not found: b"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
 func TestStringLiteralExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -257,6 +405,11 @@ func TestStringLiteralExpression(t *testing.T) {
 	}{
 		{"\"Hello, World!\";", "Hello, World!"},
 		{"\"Sei\" + \"Kin\";", "SeiKin"},
+		{`"a\tb\n"`, "a\tb\n"},
+		{"`C:\\path\\n`", `C:\path\n`},
+		{`let name = "ganyariya"; "hello ${name}!"`, "hello ganyariya!"},
+		{`let xs = [1, 2]; "${len(xs)} items: ${xs}, ${"nested ${xs[0] + 1}"}"`, "2 items: [1, 2], nested 2"},
+		{"\"\"\"\n  a\n  ${1 + 1}\n\"\"\"", "  a\n  2\n"},
 	}
 	for _, tt := range tests {
 		evaluated := callEval(tt.input)
@@ -303,6 +456,9 @@ func TestArrayIndexExpressions(t *testing.T) {
 		{"let a = [1, 2, 3]; a[0] + a[1] + a[2]", 6},
 		{"[1, 2, 3][3]", nil},
 		{"[1, 2, 3][-10]", nil},
+		{"[1, 2, 3][-1]", 3},
+		{"[1, 2, 3][-3]", 1},
+		{"[1, 2, 3][-4]", nil},
 	}
 	for _, tt := range tests {
 		evaluated := callEval(tt.input)
@@ -315,6 +471,30 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc"[0]`, "a"},
+		{`"こんにちは"[1]`, "ん"},
+		{`let 挨拶 = "やあ🐒"; 挨拶[2]`, "🐒"},
+		{`"abc"[3]`, nil},
+		{`"abc"[-1]`, "c"},
+		{`"こんにちは"[-2]`, "ち"},
+		{`"abc"[-4]`, nil},
+	}
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		str, ok := tt.expected.(string)
+		if ok {
+			checkStringObject(t, evaluated, str, tt.input)
+		} else {
+			checkNullObject(t, evaluated, tt.input)
+		}
+	}
+}
+
 func TestHashLiterals(t *testing.T) {
 	input := `
 		let two = "two";
@@ -342,12 +522,12 @@ func TestHashLiterals(t *testing.T) {
 		(&object.Boolean{Value: false}).HashKey():  6,
 	}
 
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	if result.Len() != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", result.Len())
 	}
 	i := 0
 	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
+		pair, ok := result.Get(expectedKey)
 		if !ok {
 			t.Fatal("no pair for given key in Pairs")
 		}
@@ -367,6 +547,9 @@ func TestHashIndexExpressions(t *testing.T) {
 		{`{}["foo"]`, nil},
 		{`{5:5}[5]`, 5},
 		{`{true:5}[true]`, 5},
+		{`{1: 5}[1.0]`, 5},
+		{`{1.5: 5}[1.5]`, 5},
+		{`{1.5: 5}[1]`, nil},
 	}
 	for _, tt := range tests {
 		evaluated := callEval(tt.input)
@@ -493,3 +676,675 @@ func TestDefineMacros(t *testing.T) {
 		t.Fatalf("body is not %q. got=%q", expectedBody, macro.Body.String())
 	}
 }
+
+func TestRegisteredOperators(t *testing.T) {
+	env := object.NewEnvironment()
+	RegisterInfixOperator(env, "=~", func(left, right object.Object) object.Object {
+		l, ok1 := left.(*object.String)
+		r, ok2 := right.(*object.String)
+		if !ok1 || !ok2 {
+			return nil
+		}
+		return nativeBoolToBooleanObject(strings.Contains(l.Value, r.Value))
+	})
+	RegisterInfixOperator(env, "..", func(left, right object.Object) object.Object {
+		l, ok1 := left.(*object.Integer)
+		r, ok2 := right.(*object.Integer)
+		if !ok1 || !ok2 {
+			return newError("range bounds must be INTEGER, got=%s .. %s", left.Type(), right.Type())
+		}
+		elements := []object.Object{}
+		for i := l.Value; i < r.Value; i++ {
+			elements = append(elements, &object.Integer{Value: i})
+		}
+		return &object.Array{Elements: elements}
+	})
+	RegisterPrefixOperator(env, "√", func(right object.Object) object.Object {
+		if value, ok := toFloat(right); ok {
+			return &object.Float{Value: math.Sqrt(value)}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"monkey" =~ "key"`, "true"},
+		{`"monkey" =~ "dog"`, "false"},
+		{`1 =~ 2`, "ERROR: 1:3: unknown operator: INTEGER =~ INTEGER"},
+		{"1 .. 2 + 2", "[1, 2, 3]"},
+		{"len(0 .. 10)", "10"},
+		{`1 .. "a"`, "ERROR: 1:3: range bounds must be INTEGER, got=INTEGER .. STRING"},
+		{"√16 * 2", "8.0"},
+		{`√"x"`, "ERROR: 1:1: unknown operator: √STRING"},
+	}
+
+	for _, tt := range tests {
+		p := parser.NewParser(lexer.NewLexer(tt.input))
+		p.RegisterInfixOperator("=~", "MATCH", parser.EQUALS)
+		p.RegisterInfixOperator("..", "RANGE", parser.SUM-1)
+		p.RegisterPrefixOperator("√", "SQRT")
+		program := p.ParseProgram()
+		assert.Empty(t, p.Errors(), tt.input)
+		result := Eval(program, object.NewEnclosedEnvironment(env))
+		assert.Equal(t, tt.expected, result.Inspect(), tt.input)
+	}
+
+	// 登録していない環境や組み込みの演算子には影響しない
+	p := parser.NewParser(lexer.NewLexer(`"monkey" =~ "key"`))
+	p.RegisterInfixOperator("=~", "MATCH", parser.EQUALS)
+	assert.Equal(t, "ERROR: 1:10: unknown operator: STRING =~ STRING", Eval(p.ParseProgram(), object.NewEnvironment()).Inspect())
+	RegisterInfixOperator(env, "+", func(left, right object.Object) object.Object { return NULL })
+	assert.Equal(t, "3", Eval(parser.NewParser(lexer.NewLexer("1 + 2")).ParseProgram(), env).Inspect())
+}
+
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let a = 1; a = 2; a;", 2},
+		{"let a = 1; a = a + 1;", 2},
+		{"let a = 1; let b = 2; a = b = 3; a + b;", 6},
+		{"let a = 10; a += 5; a;", 15},
+		{"let a = 10; a -= 5; a;", 5},
+		{"let a = 10; a *= 5; a;", 50},
+		{"let a = 10; a /= 5; a;", 2},
+		{"let a = 1; a += 0.5; a;", 1.5},
+		{`let s = "mon"; s += "key"; s;`, "monkey"},
+		// 外側の束縛を書き換える
+		{"let a = 1; let f = fn() { a = a + 1; }; f(); f(); a;", 3},
+		{"let counter = fn() { let n = 0; fn() { n += 1 } }; let c = counter(); c(); c(); c();", 3},
+		{"let sum = 0; let add = fn(xs) { if (len(xs) > 0) { sum += first(xs); add(rest(xs)); } }; add([1, 2, 3]); sum;", 6},
+		// 内側で let した変数は外側に影響しない
+		{"let a = 1; let f = fn() { let a = 5; a = 10; }; f(); a;", 1},
+		{"let f = fn(a) { a = 10; a }; let a = 1; f(a) + a;", 11},
+		{"b = 1;", "ERROR: 1:3: cannot assign to undeclared variable: b"},
+		{"let f = fn() { x += 1 }; f();", "ERROR: 1:18: cannot assign to undeclared variable: x"},
+		{`let a = 1; a += "x";`, "ERROR: 1:14: type mismatch: INTEGER + STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			checkIntegerObject(t, evaluated, int64(expected), tt.input)
+		case float64:
+			checkFloatObject(t, evaluated, expected, tt.input)
+		case string:
+			if err, ok := evaluated.(*object.Error); ok {
+				assert.Equal(t, expected, err.Inspect(), tt.input)
+			} else {
+				checkStringObject(t, evaluated, expected, tt.input)
+			}
+		}
+	}
+}
+
+func TestLoops(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let i = 0; while (i < 10) { i += 1; } i;", 10},
+		{"let i = 0; while (false) { i += 1; }", nil},
+		{"let i = 0; while (true) { i += 1; if (i == 5) { break; } } i;", 5},
+		{"let i = 0; let sum = 0; while (i < 10) { i += 1; if (i % 2 == 0) { continue; } sum += i; } sum;", 25},
+		// 大きな繰り返しでもスタックを消費しない
+		{"let i = 0; while (i < 100000) { i += 1; } i;", 100000},
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { sum += x; } sum;", 10},
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { break } sum += x; } sum;", 3},
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { if (x == 3) { continue } sum += x; } sum;", 7},
+		{`let s = ""; for (ch in "モンキー") { s = ch + s; } s;`, "ーキンモ"},
+		{`let sum = 0; for (k in {1: "a", 2: "b", 3: "c"}) { sum += k; } sum;`, 6},
+		{"for (x in []) { x }", nil},
+		// 入れ子のループの break は内側のループだけを抜ける
+		{"let n = 0; for (x in [1, 2, 3]) { for (y in [1, 2, 3]) { if (y == 2) { break } n += 1; } } n;", 3},
+		// return はループを抜けて関数から戻る
+		{"let find = fn(xs, v) { for (x in xs) { if (x == v) { return true } } false }; find([1, 2, 3], 2);", true},
+		{"let find = fn(xs, v) { for (x in xs) { if (x == v) { return true } } false }; find([1, 2, 3], 5);", false},
+		// ループ変数は繰り返しごとに束縛される
+		{"let fs = []; for (x in [1, 2]) { fs = push(fs, fn() { x }); } fs[0]() + fs[1]() * 10;", 21},
+		{"let x = 100; for (x in [1, 2]) { x } x;", 100},
+		{"for (x in 1) { x }", "ERROR: 1:11: cannot iterate over INTEGER"},
+		{"while (x) { 1 }", "ERROR: 1:8: identifier not found: x"},
+		{"for (x in [1]) { x + true }", "ERROR: 1:20: type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			checkIntegerObject(t, evaluated, int64(expected), tt.input)
+		case bool:
+			checkBooleanObject(t, evaluated, expected, tt.input)
+		case string:
+			if err, ok := evaluated.(*object.Error); ok {
+				assert.Equal(t, expected, err.Inspect(), tt.input)
+			} else {
+				checkStringObject(t, evaluated, expected, tt.input)
+			}
+		case nil:
+			checkNullObject(t, evaluated, tt.input)
+		}
+	}
+}
+
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"true && true", true},
+		{"true && false", false},
+		{"false || true", true},
+		{"false || false", false},
+		// 結果を決めたオペランドを返す
+		{"1 && 2", 2},
+		{"0 && 2", 0},
+		{"0 || 3", 3},
+		{`"" || "default"`, "default"},
+		{`"value" || "default"`, "value"},
+		{"1 < 2 && 3 > 2", true},
+		{"1 == 2 || 2 == 2 && 3 == 4", false},
+		// 右辺は必要なときだけ評価される
+		{"false && undefined", false},
+		{"true || undefined", true},
+		{"let xs = []; len(xs) > 0 && xs[0] > 1", false},
+		{"let n = 0; let inc = fn() { n += 1; true }; false && inc(); true || inc(); n", 0},
+		{"let n = 0; let inc = fn() { n += 1; true }; true && inc(); false || inc(); n", 2},
+		{"true && undefined", "ERROR: 1:9: identifier not found: undefined"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			checkIntegerObject(t, evaluated, int64(expected), tt.input)
+		case bool:
+			checkBooleanObject(t, evaluated, expected, tt.input)
+		case string:
+			if err, ok := evaluated.(*object.Error); ok {
+				assert.Equal(t, expected, err.Inspect(), tt.input)
+			} else {
+				checkStringObject(t, evaluated, expected, tt.input)
+			}
+		}
+	}
+}
+
+func TestBigIntPromotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4294967296 * 4294967296", "18446744073709551616"},
+		{"2 ** 64", "18446744073709551616"},
+		{"10 ** 30", "1000000000000000000000000000000"},
+		{"(-3) ** 41", "-36472996377170786403"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"(-9223372036854775807 - 1) / -1", "9223372036854775808"},
+		// 結果が int64 に収まれば Integer に戻る
+		{"(2 ** 64) / (2 ** 60)", "16"},
+		{"(2 ** 64) - (2 ** 64) + 1", "1"},
+		{"(2 ** 64) % 10", "6"},
+		{"-(2 ** 70) / 3", "-393530540239137101141"},
+		{"(2 ** 64) > 9223372036854775807", "true"},
+		{"(2 ** 64) == (2 ** 64)", "true"},
+		{"(2 ** 64) != 2 ** 65", "true"},
+		{"(2 ** 64) + 0.5", "18446744073709552000.0"},
+		{"(2 ** 64) >> 60", "16"},
+		{"(2 ** 64) << 1", "36893488147419103232"},
+		// 左シフトのあふれも BigInt に昇格する
+		{"1 << 63", "9223372036854775808"},
+		{"1 << 64", "18446744073709551616"},
+		{"3 << 62", "13835058055282163712"},
+		{"-3 << 62", "-13835058055282163712"},
+		{"-1 << 64", "-18446744073709551616"},
+		{"(2 ** 64) & 0xFF", "0"},
+		{"~(2 ** 64)", "-18446744073709551617"},
+		{"2 ** -1", "0.5"},
+		{"let x = 9223372036854775807; x += 1; x", "9223372036854775808"},
+		{"abs(-(2 ** 64))", "18446744073709551616"},
+		{`int("123456789012345678901234567890")`, "123456789012345678901234567890"},
+		{"int(1e20)", "100000000000000000000"},
+		{"float(2 ** 64)", "18446744073709552000.0"},
+		{"pow(3, 40)", "12157665459056928801"},
+		{"{2 ** 64: 1}[2 ** 64]", "1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	classify := `let f = fn(v) {
+  match (v) {
+    0 => "zero",
+    -1 => "minus one",
+    "s" => "string",
+    true => "true",
+    [] => "empty",
+    [x] => "one ${x}",
+    [h, _, ...t] => "head ${h} rest ${len(t)}",
+    {"type": "circle", "r": r} => "circle ${r}",
+    {"type": t} if t == "square" => "guarded square",
+    {"type": t} => "shape " + t,
+    n if n == 100 => "hundred",
+    _ => "other",
+  }
+};
+`
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{classify + "f(0)", "zero"},
+		{classify + "f(0.0)", "zero"},
+		{classify + "f(-1)", "minus one"},
+		{classify + `f("s")`, "string"},
+		{classify + "f(true)", "true"},
+		{classify + "f([])", "empty"},
+		{classify + "f([7])", "one 7"},
+		{classify + "f([1, 2])", "head 1 rest 0"},
+		{classify + "f([1, 2, 3, 4])", "head 1 rest 2"},
+		{classify + `f({"type": "circle", "r": 2})`, "circle 2"},
+		{classify + `f({"type": "square", "side": 3})`, "guarded square"},
+		{classify + `f({"type": "triangle"})`, "shape triangle"},
+		{classify + "f(100)", "hundred"},
+		{classify + "f(5)", "other"},
+		{classify + `f({"r": 1})`, "other"},
+		{"match ([1, [2, 3]]) { [a, [b, ...c]] => [a, b, c] }", "[1, 2, [3]]"},
+		{"match ([1, 2, 3]) { [first, ..._] => first }", "1"},
+		// 束縛は分岐の中だけで有効
+		{"let x = 1; match (2) { x => x }; x", "1"},
+		{"match (3) { 1 => 1, 2 => 2 }", "ERROR: 1:1: no match for 3"},
+		{"match ([1, 2]) { [x] => x }", "ERROR: 1:1: no match for [1, 2]"},
+		{"match (1) { x if y => x }", "ERROR: 1:18: identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let pair = fn() { [1, 2] }; let [a, b] = pair(); a + b", "3"},
+		{"let [h, ...t] = [1, 2, 3]; [h, t]", "[1, [2, 3]]"},
+		{"let [h, ...t] = [1]; t", "[]"},
+		{"let [_, second] = [1, 2]; second", "2"},
+		{`let {"name": n, "age": a} = {"name": "Bob", "age": 3, "id": 1}; "${n}:${a}"`, "Bob:3"},
+		{`let {"pos": [x, y]} = {"pos": [4, 5]}; x * y`, "20"},
+		{"let [[a, b], c] = [[1, 2], 3]; a + b + c", "6"},
+		{"let f = fn(xs) { let [a, ...rest] = xs; rest }; f([1, 2]);", "[2]"},
+		// 形が一致しなければ、一致しなかったパターンの位置でエラーになる
+		{"let [a, b] = [1];", "ERROR: 1:5: cannot destructure: expected array of length 2, got 1"},
+		{"let [a, b, ...c] = [1];", "ERROR: 1:5: cannot destructure: expected array of at least length 2, got 1"},
+		{"let [a] = 5;", "ERROR: 1:5: cannot destructure: expected ARRAY, got INTEGER"},
+		{`let {"name": n} = {"age": 1};`, "ERROR: 1:6: cannot destructure: missing key name"},
+		{`let {"name": n} = [1];`, "ERROR: 1:5: cannot destructure: expected HASH, got ARRAY"},
+		{"let [a, [b]] = [1, 2];", "ERROR: 1:9: cannot destructure: expected ARRAY, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestPipelineAndArrowFunctions(t *testing.T) {
+	prelude := `let map = fn(xs, f) { let out = []; for (x in xs) { out = push(out, f(x)) } out };
+let filter = fn(xs, pred) { let out = []; for (x in xs) { if (pred(x)) { out = push(out, x) } } out };
+`
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let double = x => x * 2; double(21)", "42"},
+		{"let add = (a, b) => a + b; add(1, 2)", "3"},
+		{"let k = () => 42; k()", "42"},
+		{"let adder = x => y => x + y; adder(1)(2)", "3"},
+		{"[1, 2, 3] |> len()", "3"},
+		{"let add = (a, b) => a + b; 1 |> add(2) |> add(3)", "6"},
+		{prelude + "[1, 2, 3] |> map(x => x * 2) |> filter(x => x > 3)", "[4, 6]"},
+		{prelude + "filter(map([1, 2, 3], fn(x) { x * 2 }), fn(x) { x > 3 })", "[4, 6]"},
+		{"let double = x => x * 2; double", "fn(x) {\n(x * 2)\n}"},
+		{"let add = (a, b) => a + b; add(1)", "ERROR: 1:31: wrong number of arguments to `add`. expected=2, got=1"},
+		{"let f = (a, b) => a; 1 |> f()", "ERROR: 1:28: wrong number of arguments to `f`. expected=2, got=1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4][2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][-2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:-1]", "[1, 2, 3]"},
+		{"[1, 2, 3, 4][-3:-1]", "[2, 3]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"[1, 2, 3, 4][-10:10]", "[1, 2, 3, 4]"},
+		{"let xs = [1, 2, 3]; let i = 1; xs[i:i + 1]", "[2]"},
+		// スライスは新しい配列を返す
+		{"let xs = [1, 2, 3]; let ys = xs[:]; ys = push(ys, 4); xs", "[1, 2, 3]"},
+		{`"hello"[1:3]`, "el"},
+		{`"こんにちは"[-2:]`, "ちは"},
+		{`"🐒abc"[:2]`, "🐒a"},
+		{`"abc"[5:]`, ""},
+		{`[1, 2]["a":]`, "ERROR: 1:7: slice index must be INTEGER, got STRING"},
+		{"5[1:2]", "ERROR: 1:2: slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestIndexAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let xs = [1, 2, 3]; xs[0] = 10; xs", "[10, 2, 3]"},
+		{"let xs = [1, 2, 3]; xs[-1] = 30; xs", "[1, 2, 30]"},
+		{"let xs = [1, 2, 3]; xs[1] += 5; xs", "[1, 7, 3]"},
+		{"let xs = [[1], [2]]; xs[1][0] = 5; xs", "[[1], [5]]"},
+		{"let xs = [0, 0]; xs[0] = xs[1] = 7; xs", "[7, 7]"},
+		{`let h = {}; h["a"] = 1; h["a"]`, "1"},
+		{`let h = {"a": 1}; h["a"] = 2; h["a"]`, "2"},
+		{`let h = {"n": 1}; h["n"] *= 10; h["n"]`, "10"},
+		{`let h = {}; for (w in ["a", "b", "a"]) { h[w] = 0 } for (w in ["a", "b", "a"]) { h[w] += 1 } [h["a"], h["b"]]`, "[2, 1]"},
+		// 配列とハッシュは参照として共有される
+		{"let a = [1, 2]; let b = a; b[0] = 9; a", "[9, 2]"},
+		{"let a = [1, 2]; let b = push(a, 3); b[0] = 9; a", "[1, 2]"},
+		{"let a = [1, 2, 3]; let b = a[:]; b[0] = 9; a", "[1, 2, 3]"},
+		{"let set = fn(xs) { xs[0] = 0 }; let a = [1]; set(a); a", "[0]"},
+		{`let h = {}; let add = fn(k) { h[k] = true }; add("x"); h["x"]`, "true"},
+		{`let h = {"k": 1}; let get = fn() { h["k"] }; delete(h, "k"); get()`, "null"},
+		{"let xs = [1]; xs[1] = 2", "ERROR: 1:21: index out of range: 1 (length 1)"},
+		{`let xs = [1]; xs["a"] = 2`, "ERROR: 1:23: array index must be INTEGER, got STRING"},
+		{`let h = {}; h["a"] += 1`, "ERROR: 1:20: key not found: a"},
+		{`let h = {}; h[[1]] = 1`, "ERROR: 1:20: unusable as hash key: ARRAY"},
+		{`let s = "abc"; s[0] = "x"`, "ERROR: 1:21: index assignment not supported: STRING"},
+		{"let xs = [1]; xs[0] = y", "ERROR: 1:23: identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestMemberExpressions(t *testing.T) {
+	counter := `let counter = {
+  "count": 0,
+  "step": 1,
+  "inc": fn(n = self.step) { self.count += n; self },
+  "get": () => self.count,
+};
+`
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let p = {"name": "Bob", "age": 3}; p.name`, "Bob"},
+		{`let p = {"pos": {"x": 1}}; p.pos.x`, "1"},
+		{`let p = {"name": "Bob"}; p.age`, "null"},
+		{`let p = {"tags": ["a", "b"]}; p.tags[1]`, "b"},
+		{`let p = {}; p.name = "Alice"; p["name"]`, "Alice"},
+		{`let p = {"n": 1}; p.n += 2; p.n`, "3"},
+		{counter + "counter.inc(); counter.inc(5); counter.get()", "6"},
+		{counter + "counter.inc().inc().count", "2"},
+		{counter + `let other = {"count": 100, "get": counter.get}; other.get()`, "100"},
+		// self は呼び出しのときだけ束縛され、元の関数には残らない
+		{counter + "let get = counter.get; get()", "ERROR: 5:16: identifier not found: self"},
+		{`let h = {"len": len}; h.len([1, 2])`, "2"},
+		{`let h = {"f": fn(self) { self }}; h.f(1)`, "1"},
+		{`let h = {"x": 1}; h.f()`, "ERROR: 1:22: undefined method f"},
+		{`let h = {"x": 1}; h.x()`, "ERROR: 1:22: not a function: INTEGER"},
+		{"let n = 1; n.x", "ERROR: 1:13: cannot access field x on INTEGER"},
+		{"[1].push(2)", "ERROR: 1:9: cannot call method push on ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestTryCatchThrow(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`try { throw "boom" } catch (e) { e.message }`, "boom"},
+		{`try { throw "boom" } catch (e) { e.kind }`, "Error"},
+		{`try { throw 42 } catch (e) { e.value + 1 }`, "43"},
+		{`try { throw {"kind": "ValidationError", "message": "bad"} } catch (e) { e.kind + ": " + e.message }`, "ValidationError: bad"},
+		{"try { 1 } catch (e) { 2 }", "1"},
+		{"try { } catch (e) { 2 }", "null"},
+		// 評価器と組み込み関数のエラーも捕まえられる
+		{`try { 1 + "a" } catch (e) { [e.kind, e.message, e.position] }`, "[TypeError, type mismatch: INTEGER + STRING, 1:9]"},
+		{`try { missing } catch (e) { [e.kind, e.line, e.column] }`, "[NameError, 1, 7]"},
+		{"try { len(1, 2) } catch (e) { e.kind }", "ArgumentError"},
+		{"try { 1 / 0 } catch (e) { e.kind }", "ZeroDivisionError"},
+		{"try { [1][5] = 0 } catch (e) { e.kind }", "IndexError"},
+		{`try { let h = {}; h["k"] += 1 } catch (e) { e.kind }`, "KeyError"},
+		{"try { 1() } catch (e) { e.kind }", "TypeError"},
+		{"try { delete({}, [1]) } catch (e) { e.kind }", "TypeError"},
+		{"try { {[1]: 2} } catch (e) { e.kind }", "TypeError"},
+		{`try { [1]["a"] = 2 } catch (e) { e.kind }`, "TypeError"},
+		{`try { "s"[0] = "t" } catch (e) { e.kind }`, "TypeError"},
+		{"try { for (x in 1) {} } catch (e) { e.kind }", "TypeError"},
+		{"try { [1].f() } catch (e) { e.kind }", "TypeError"},
+		{"try { {}.f() } catch (e) { e.kind }", "KeyError"},
+		{"try { 1.x } catch (e) { e.kind }", "TypeError"},
+		{`try { [1][1:"a"] } catch (e) { e.kind }`, "TypeError"},
+		{`try { int("abc") } catch (e) { e.kind }`, "ValueError"},
+		{"try { 1 << -1 } catch (e) { e.kind }", "ValueError"},
+		{"try { 1 << 70000 } catch (e) { e.kind }", "OverflowError"},
+		{"try { match (1) { 2 => 3 } } catch (e) { e.kind }", "ValueError"},
+		{`try { let [a, b] = 5 } catch (e) { e["kind"] }`, "ValueError"},
+		{"let f = fn(n) { f(n + 1) }; try { f(0) } catch (e) { [e.kind, e.message] }", "[RecursionError, maximum call depth exceeded (10000)]"},
+		// 上限は呼び出しの深さなので、上限より少ない深さの再帰や上限を超える回数の呼び出しは問題ない
+		{"let g = fn(n) { if (n == 0) { 0 } else { 1 + g(n - 1) } }; g(9000)", "9000"},
+		{"let h = fn(x) { x }; let n = 0; while (n < 20000) { n = h(n) + 1 } n", "20000"},
+		{`try { let [a, b] = [1] } catch (e) { e["kind"] }`, "ValueError"},
+		{`try { let {"k": v} = {} } catch (e) { e["kind"] }`, "ValueError"},
+		{`try { match ([1]) { [2] => 0 } } catch (e) { e["kind"] }`, "ValueError"},
+		{"try { match (1) { 2 => 2 } } catch (e) { e.message }", "no match for 1"},
+		// 関数の中で投げたエラーを呼び出し側で捕まえる
+		{`let check = fn(x) { if (x < 0) { throw "negative" } x }; try { check(-1) } catch (e) { e.message }`, "negative"},
+		// エラーを集めて処理を続ける
+		{`let check = fn(x) { if (x < 0) { throw "negative: ${x}" } x };
+let errors = [];
+for (v in [1, -2, 3, -4]) { try { check(v) } catch (e) { errors = push(errors, e.message) } }
+errors`, "[negative: -2, negative: -4]"},
+		// 投げ直し
+		{`try { try { throw {"kind": "A", "message": "m"} } catch (e) { throw e } } catch (e) { e.kind + e.message }`, "Am"},
+		{`try { try { throw "inner" } catch (e) { throw "outer" } } catch (e) { e.message }`, "outer"},
+		// finally は常に評価される
+		{"let log = []; try { log = push(log, 1) } finally { log = push(log, 2) }; log", "[1, 2]"},
+		{`let log = []; try { throw "x" } catch (e) { log = push(log, 1) } finally { log = push(log, 2) }; log`, "[1, 2]"},
+		{`let log = []; try { try { throw "x" } finally { log = push(log, "f") } } catch (e) { log = push(log, e.message) }; log`, "[f, x]"},
+		{"let f = fn() { try { return 1 } finally { return 2 } }; f()", "2"},
+		{"let f = fn() { try { return 1 } finally { 2 } }; f()", "1"},
+		{"let n = 0; while (true) { try { n += 1; if (n > 20) { break } } finally { n += 10 } } n", "33"},
+		// catch されなかったエラーはそのまま伝わる
+		{`throw "uncaught"`, "ERROR: 1:1: uncaught"},
+		{`try { throw "x" } finally { 1 }`, "ERROR: 1:7: x"},
+		{`try { throw "x" } catch (e) { throw "y: " + e.message }`, "ERROR: 1:31: y: x"},
+		{`try { 1 } finally { throw "f" }`, "ERROR: 1:21: f"},
+		// catch の変数は catch の中だけで有効
+		{`try { throw "x" } catch (e) { 1 }; e`, "ERROR: 1:36: identifier not found: e"},
+	}
+
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestErrorStackTrace(t *testing.T) {
+	input := `let inner = fn(x, s) {
+  x + s
+};
+let outer = fn(x, s) { inner(x, s) };
+outer(1, "a");`
+	evaluated := callEval(input)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+	expected := []object.StackFrame{
+		{Function: "inner", Pos: errObj.Stack[0].Pos, Args: `1, "a"`},
+		{Function: "outer", Pos: errObj.Stack[1].Pos, Args: `1, "a"`},
+	}
+	assert.Equal(t, expected, errObj.Stack)
+	assert.Equal(t, "4:24", errObj.Stack[0].Pos.String())
+	assert.Equal(t, "5:1", errObj.Stack[1].Pos.String())
+	assert.Equal(t, "ERROR: 2:5: type mismatch: INTEGER + STRING", errObj.Inspect())
+
+	tests := []struct {
+		input    string
+		expected []string // 内側の呼び出しから順に「関数名(実引数)」
+	}{
+		{"1 + true", []string{}},
+		// 本体に入る前のエラー（引数の数の誤り）は呼び出し元のエラー
+		{"let f = fn(a) { a }; f()", []string{}},
+		{"let f = fn(a) { a }; let g = fn() { f() }; g()", []string{"g()"}},
+		{`let f = fn(n) { if (n == 0) { throw "x" } f(n - 1) }; f(2)`, []string{"f(0)", "f(1)", "f(2)"}},
+		{`let p = {"greet": fn() { self.missing() }}; p.greet()`, []string{"greet()"}},
+		{"let xs = [1, 2]; let f = fn(xs, g) { g(xs) }; f(xs, fn(x) { x + true })", []string{"<anonymous>([1, 2])", "f([1, 2], fn)"}},
+		{`let f = fn(s) { s - 1 }; f("abcdefghijklmnopqrstuvwxyz")`, []string{`f("abcdefghijklmnop...)`}},
+		{"let f = fn(x) { x + true }; 1 |> f()", []string{"f(1)"}},
+		// 大きな値・自分自身を含む値は必要な分だけ文字列にする
+		{"let xs = [1]; xs[0] = xs; let f = fn(a) { a + true }; f(xs)", []string{"f([[[[[[[[[[[[[[[[[...)"}},
+		{`let f = fn(h) { h + true }; f({"a": "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "c": 1})`, []string{"f({a: bbbbbbbbbbbbb...)"}},
+		{`let f = fn(h) { h + true }; f({"a": [1, 2]})`, []string{"f({a: [1, 2]})"}},
+		// 捕まえたエラーはトレースに残らない
+		{`let f = fn() { try { 1 + true } catch (e) { 0 } }; let g = fn() { f() + true }; g()`, []string{"g()"}},
+	}
+	for _, tt := range tests {
+		errObj, ok := callEval(tt.input).(*object.Error)
+		if !ok {
+			t.Fatalf("object is not Error. input=%q", tt.input)
+		}
+		frames := []string{}
+		for _, f := range errObj.Stack {
+			frames = append(frames, fmt.Sprintf("%s(%s)", f.FunctionName(), f.Args))
+		}
+		assert.Equal(t, tt.expected, frames, tt.input)
+	}
+}
+
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2] == [1, 2]", "true"},
+		{"[1, 2] != [1, 2]", "false"},
+		{"[1, 2] == [2, 1]", "false"},
+		{"[1, 2] == [1, 2, 3]", "false"},
+		{"[1, [2, [3]]] == [1, [2, [3]]]", "true"},
+		{"[1, 2.0] == [1.0, 2]", "true"},
+		{`[1] == ["1"]`, "false"},
+		{`{"a": 1, "b": [2]} == {"b": [2], "a": 1}`, "true"},
+		{`{"a": 1} == {"a": 2}`, "false"},
+		{`{"a": 1} == {"a": 1, "b": 2}`, "false"},
+		{`{"a": null} == {"b": null}`, "false"},
+		{"[] == {}", "false"},
+		{"null == null", "true"},
+		{"[null] == [first([])]", "true"},
+		{"quote(1 + x) == quote(1 + x)", "true"},
+		{"quote(1 + x) == quote(1 + y)", "false"},
+		{"quote(fn(a) { a * 2 }) == quote(fn(a) { a * 2 })", "true"},
+		{"let f = fn() { 1 }; f == f", "true"},
+		{"fn() { 1 } == fn() { 1 }", "false"},
+		{"let a = [1]; a[0] = a; let b = [1]; b[0] = b; a == b", "true"},
+		{"let a = [1, 2]; a[0] = a; let b = [1, 3]; b[0] = b; a == b", "false"},
+		{`let h = {}; h["self"] = h; h == h`, "true"},
+		{`let a = {"x": 1}; a["self"] = a; let b = {"x": 1}; b["self"] = b; a == b`, "true"},
+		// is は同じオブジェクトかどうかを判定する
+		{"let a = [1, 2]; let b = a; a is b", "true"},
+		{"[1, 2] is [1, 2]", "false"},
+		{"let a = [1]; let b = a; b[0] = 2; a is b && a == [2]", "true"},
+		{"null is null", "true"},
+		{"true is (1 == 1)", "true"},
+		{"let f = fn() { 1 }; f is f", "true"},
+		{"len is len", "true"},
+		{"!([1] is [1])", "true"},
+	}
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestHashInsertionOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": 1, "a": 2, 3: "c", true: 4}`, "{b: 1, a: 2, 3: c, true: 4}"},
+		{`{"a": 1, "b": 2, "a": 3}`, "{a: 3, b: 2}"},
+		// 1 と 1.0 は同じキーなので、最初に書いたキーのまま値だけが変わる
+		{`{1: "a", 1.0: "b"}`, "{1: b}"},
+		{`{1.0: "a", 1: "b"}`, "{1.0: b}"},
+		{`let h = {1: "a"}; h[1.0] = "b"; h`, "{1: b}"},
+		{`keys({"z": 1, "y": 2, "x": 3})`, "[z, y, x]"},
+		{`values({"z": 1, "y": 2, "x": 3})`, "[1, 2, 3]"},
+		{`keys({})`, "[]"},
+		{`keys([1])`, "ERROR: 1:5: argument to `keys` must be HASH, got ARRAY"},
+		{`let h = {"x": 1}; h["b"] = 2; h["a"] = 3; h`, "{x: 1, b: 2, a: 3}"},
+		// 既存のキーへの代入は位置を変えず、削除して追加し直すと末尾に移る
+		{`let h = {"a": 1, "b": 2}; h["a"] = 9; h`, "{a: 9, b: 2}"},
+		{`let h = {"a": 1, "b": 2, "c": 3}; delete(h, "a"); h["a"] = 4; h`, "{b: 2, c: 3, a: 4}"},
+		{`let h = {"a": 1, "b": 2, "c": 3}; delete(h, "b"); keys(h)`, "[a, c]"},
+		{`let out = []; for (k in {"c": 1, "a": 2, "b": 3}) { out = push(out, k) } out`, "[c, a, b]"},
+		// リテラルはキー・値の順に書かれた順で評価される
+		{`let log = []; let note = fn(x) { log = push(log, x); x }; {note("k1"): note(1), note("k2"): note(2)}; log`, "[k1, 1, k2, 2]"},
+		{`try { throw "x" } catch (e) { keys(e) }`, "[message, kind, position, line, column, value]"},
+	}
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
+
+func TestSelfReferentialContainers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {"k": 1}; h["self"] = h; h`, "{k: 1, self: {...}}"},
+		{`let xs = [1]; xs[0] = xs; xs`, "[[...]]"},
+		{`let h = {"k": 1}; h["self"] = h; "${h}"`, "{k: 1, self: {...}}"},
+		{`let h = {}; h["self"] = h; try { throw h } catch (e) { e.message }`, "{self: {...}}"},
+	}
+	for _, tt := range tests {
+		evaluated := callEval(tt.input)
+		assert.Equal(t, tt.expected, evaluated.Inspect(), tt.input)
+	}
+}
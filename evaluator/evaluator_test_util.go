@@ -24,6 +24,14 @@ func checkIntegerObject(t *testing.T, obj object.Object, expected int64, text st
 	assert.Equal(t, expected, result.Value)
 }
 
+func checkFloatObject(t *testing.T, obj object.Object, expected float64, text string) {
+	result, ok := obj.(*object.Float)
+	assert.True(t, ok, text)
+	if ok {
+		assert.InDelta(t, expected, result.Value, 1e-9, text)
+	}
+}
+
 func checkBooleanObject(t *testing.T, obj object.Object, expected bool, text string) {
 	result, ok := obj.(*object.Boolean)
 	assert.True(t, ok, text)
@@ -2,6 +2,8 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
 
 	"github.com/ganyariya/go_monkey/object"
 )
@@ -9,9 +11,61 @@ import (
 func newError(format string, x ...interface{}) *object.Error {
 	return &object.Error{Message: fmt.Sprintf(format, x...)}
 }
+
+// 種類（object.TYPE_ERROR など）つきのエラー。catch した側は e.kind で区別できる
+func newKindError(kind string, format string, x ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, x...), Kind: kind}
+}
 func isError(obj object.Object) bool {
 	if obj != nil {
 		return obj.Type() == object.ERROR_OBJ
 	}
 	return false
 }
+
+func isNumber(obj object.Object) bool {
+	return isInteger(obj) || obj.Type() == object.FLOAT_OBJ
+}
+
+// Integer / Float を float64 に変換する（数値型の昇格）
+func toFloat(obj object.Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value), true
+	case *object.BigInt:
+		value, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return value, true
+	case *object.Float:
+		return obj.Value, true
+	default:
+		return 0, false
+	}
+}
+
+// オーバーフローを検出する足し算・引き算・掛け算（int64 に収まらなければ false を返す）
+func addInt64(a, b int64) (int64, bool) {
+	c := a + b
+	if (c > a) != (b > 0) {
+		return 0, false
+	}
+	return c, true
+}
+
+func subInt64(a, b int64) (int64, bool) {
+	c := a - b
+	if (c < a) != (b > 0) {
+		return 0, false
+	}
+	return c, true
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if c/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, false
+	}
+	return c, true
+}
@@ -0,0 +1,84 @@
+package evaluator
+
+import (
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/object"
+)
+
+/*
+throw value;
+文字列などはその Inspect() がメッセージになる
+{"kind": ..., "message": ...} のハッシュを投げると種類とメッセージを指定できる（catch した e をそのまま投げ直せる）
+*/
+func evalThrowStatement(stmt *ast.ThrowStatement, env *object.Environment) object.Object {
+	value := Eval(stmt.Value, env)
+	if isError(value) {
+		return value
+	}
+	err := &object.Error{Message: value.Inspect(), Value: value}
+	if hash, ok := value.(*object.Hash); ok {
+		if message, ok := hashField(hash, "message").(*object.String); ok {
+			err.Message = message.Value
+		}
+		if kind, ok := hashField(hash, "kind").(*object.String); ok {
+			err.Kind = kind.Value
+		}
+	}
+	return err
+}
+
+/*
+Block を評価してエラーになれば、エラーをハッシュ（message, kind, position, line, column, value）にして
+Param に束縛し Catch を評価する。throw されたエラーも評価器・組み込み関数のエラーも同じように捕まえられる
+Finally は常に最後に評価する。Finally の中で return / break / continue / エラーが起きたときはそちらが優先される
+*/
+func evalTryExpression(exp *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(exp.Block, env)
+	if err, ok := result.(*object.Error); ok && exp.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		catchEnv.Set(exp.Param.Value, errorToHash(err))
+		result = Eval(exp.Catch, catchEnv)
+	}
+	if exp.Finally != nil {
+		finally := Eval(exp.Finally, env)
+		if finally != nil {
+			switch finally.Type() {
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+				return finally
+			}
+		}
+	}
+	if result == nil {
+		return NULL
+	}
+	return result
+}
+
+// catch (e) で束縛するハッシュ
+func errorToHash(err *object.Error) *object.Hash {
+	value := err.Value
+	if value == nil {
+		value = NULL
+	}
+	hash := object.NewHash()
+	setHashField(hash, "message", &object.String{Value: err.Message})
+	setHashField(hash, "kind", &object.String{Value: err.KindName()})
+	setHashField(hash, "position", &object.String{Value: err.Pos.String()})
+	setHashField(hash, "line", &object.Integer{Value: int64(err.Pos.Line)})
+	setHashField(hash, "column", &object.Integer{Value: int64(err.Pos.Column)})
+	setHashField(hash, "value", value)
+	return hash
+}
+
+func hashField(hash *object.Hash, name string) object.Object {
+	pair, ok := hash.Get((&object.String{Value: name}).HashKey())
+	if !ok {
+		return NULL
+	}
+	return pair.Value
+}
+
+func setHashField(hash *object.Hash, name string, value object.Object) {
+	key := &object.String{Value: name}
+	hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+}
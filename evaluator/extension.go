@@ -0,0 +1,62 @@
+package evaluator
+
+import (
+	"github.com/ganyariya/go_monkey/object"
+)
+
+/*
+parser.RegisterPrefixOperator / parser.RegisterInfixOperator で追加した演算子に Go で意味を与える
+
+	env := object.NewEnvironment()
+	evaluator.RegisterInfixOperator(env, "=~", func(left, right object.Object) object.Object { ... })
+	evaluator.Eval(program, env)
+
+演算子は env（とそこから作られる環境）での評価にだけ効き、Parser と同じく他の評価には影響しない。
+組み込みの演算子（`+` や `==` など）は上書きできない。nil を返すと未知の演算子としてエラーになる。
+エラーにしたいときは *object.Error を返す。登録は Eval を呼び出す前に行う。
+*/
+type (
+	PrefixOperatorFn = object.PrefixOperatorFn
+	InfixOperatorFn  = object.InfixOperatorFn
+)
+
+func RegisterPrefixOperator(env *object.Environment, operator string, fn PrefixOperatorFn) {
+	env.OwnOperators().RegisterPrefix(operator, fn)
+}
+
+func RegisterInfixOperator(env *object.Environment, operator string, fn InfixOperatorFn) {
+	env.OwnOperators().RegisterInfix(operator, fn)
+}
+
+// 組み込みの演算子（独自の演算子の表を引かずに評価する）
+var builtinPrefixOperators = map[string]bool{"!": true, "-": true, "~": true}
+var builtinInfixOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "**": true,
+	"&": true, "|": true, "^": true, "<<": true, ">>": true,
+	"==": true, "!=": true, "<": true, ">": true, "<=": true, ">=": true,
+	"is": true, "&&": true, "||": true,
+}
+
+func applyRegisteredPrefixOperator(operator string, right object.Object, env *object.Environment) object.Object {
+	if builtinPrefixOperators[operator] {
+		return nil
+	}
+	if ops := env.Operators(); ops != nil {
+		if fn, ok := ops.Prefix(operator); ok {
+			return fn(right)
+		}
+	}
+	return nil
+}
+
+func applyRegisteredInfixOperator(operator string, left, right object.Object, env *object.Environment) object.Object {
+	if builtinInfixOperators[operator] {
+		return nil
+	}
+	if ops := env.Operators(); ops != nil {
+		if fn, ok := ops.Infix(operator); ok {
+			return fn(left, right)
+		}
+	}
+	return nil
+}
@@ -30,7 +30,7 @@ func DefineMacros(program *ast.Program, env *object.Environment) {
 */
 func isMacroDefinition(node ast.Statement) bool {
 	letStmt, ok := node.(*ast.LetStatement)
-	if !ok {
+	if !ok || letStmt.Name == nil {
 		return false
 	}
 	_, ok = letStmt.Value.(*ast.MacroExpression)
@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/object"
+)
+
+func evalMatchExpression(me *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(me.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+	for _, arm := range me.Arms {
+		bindings := map[string]object.Object{}
+		if err := matchPattern(arm.Pattern, subject, env, bindings); err != nil {
+			continue
+		}
+		armEnv := object.NewEnclosedEnvironment(env)
+		for name, obj := range bindings {
+			armEnv.Set(name, obj)
+		}
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !guard.AsBool() {
+				continue
+			}
+		}
+		return Eval(arm.Body, armEnv)
+	}
+	return newKindError(object.VALUE_ERROR, "no match for %s", subject.Inspect())
+}
+
+/*
+let [a, b, ...rest] = value; のようにパターンのすべての変数を env に束縛する
+形が一致しなければ、一致しなかった箇所の位置とともにエラーを返す（変数は 1 つも束縛しない）
+*/
+func evalDestructuring(pattern ast.Pattern, value object.Object, env *object.Environment) object.Object {
+	bindings := map[string]object.Object{}
+	if err := matchPattern(pattern, value, env, bindings); err != nil {
+		err.Message = "cannot destructure: " + err.Message
+		return err
+	}
+	for name, obj := range bindings {
+		env.Set(name, obj)
+	}
+	return value
+}
+
+/*
+value を pattern と照合し、束縛する変数を bindings に書き込む
+一致しなければ「どこが一致しなかったか」を表すエラーを返す（match は次の分岐を試し、分割代入はそのまま報告する）
+*/
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment, bindings map[string]object.Object) *object.Error {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return nil
+	case *ast.BindingPattern:
+		bindings[pattern.Name.Value] = value
+		return nil
+	case *ast.LiteralPattern:
+		literal := Eval(pattern.Value, env)
+		if evalInfixOperator("==", literal, value) != TRUE {
+			return patternError(pattern, "expected %s, got %s", literal.Inspect(), value.Inspect())
+		}
+		return nil
+	case *ast.ArrayPattern:
+		return matchArrayPattern(pattern, value, env, bindings)
+	case *ast.HashPattern:
+		return matchHashPattern(pattern, value, env, bindings)
+	}
+	return patternError(pattern, "unsupported pattern %s", pattern.String())
+}
+
+func matchArrayPattern(pattern *ast.ArrayPattern, value object.Object, env *object.Environment, bindings map[string]object.Object) *object.Error {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return patternError(pattern, "expected ARRAY, got %s", value.Type())
+	}
+	n := len(pattern.Elements)
+	if pattern.Rest == nil && len(array.Elements) != n {
+		return patternError(pattern, "expected array of length %d, got %d", n, len(array.Elements))
+	}
+	if pattern.Rest != nil && len(array.Elements) < n {
+		return patternError(pattern, "expected array of at least length %d, got %d", n, len(array.Elements))
+	}
+	for i, element := range pattern.Elements {
+		if err := matchPattern(element, array.Elements[i], env, bindings); err != nil {
+			return err
+		}
+	}
+	if pattern.Rest != nil && pattern.Rest.Value != "_" {
+		rest := make([]object.Object, len(array.Elements)-n)
+		copy(rest, array.Elements[n:])
+		bindings[pattern.Rest.Value] = &object.Array{Elements: rest}
+	}
+	return nil
+}
+
+func matchHashPattern(pattern *ast.HashPattern, value object.Object, env *object.Environment, bindings map[string]object.Object) *object.Error {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return patternError(pattern, "expected HASH, got %s", value.Type())
+	}
+	for i, keyExp := range pattern.Keys {
+		keyObj := Eval(keyExp, env)
+		key, ok := keyObj.(object.Hashable)
+		if !ok {
+			return patternError(keyExp, "unusable as hash key: %s", keyExp.String())
+		}
+		pair, ok := hash.Get(key.HashKey())
+		if !ok {
+			return patternError(keyExp, "missing key %s", keyObj.Inspect())
+		}
+		if err := matchPattern(pattern.Values[i], pair.Value, env, bindings); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func patternError(node ast.Node, format string, a ...interface{}) *object.Error {
+	err := newKindError(object.VALUE_ERROR, format, a...)
+	err.Pos = node.Pos()
+	return err
+}
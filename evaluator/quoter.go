@@ -55,6 +55,9 @@ func convertObjectToASTNode(obj object.Object) ast.Node {
 	case *object.Integer:
 		t := token.Token{Type: token.INT, Literal: fmt.Sprintf("%d", obj.Value)}
 		return &ast.IntegerLiteralExpression{Token: t, Value: obj.Value}
+	case *object.Float:
+		t := token.Token{Type: token.FLOAT, Literal: obj.Inspect()}
+		return &ast.FloatLiteralExpression{Token: t, Value: obj.Value}
 	case *object.Boolean:
 		t := token.Token{}
 		if obj.Value {
@@ -63,6 +66,8 @@ func convertObjectToASTNode(obj object.Object) ast.Node {
 			t = token.Token{Type: token.FALSE, Literal: "false"}
 		}
 		return &ast.BooleanExpression{Token: t, Value: obj.Value}
+	case *object.Null:
+		return &ast.NullLiteralExpression{Token: token.Token{Type: token.NULL, Literal: "null"}}
 	case *object.Quote:
 		return obj.Node
 	default:
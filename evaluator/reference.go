@@ -8,4 +8,8 @@ var (
 	TRUE  = &object.Boolean{Value: true}
 	FALSE = &object.Boolean{Value: false}
 	NULL  = &object.Null{}
+
+	// ループ制御のシグナル
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
 )
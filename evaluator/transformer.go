@@ -1,7 +1,10 @@
 package evaluator
 
 import (
-	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 
 	"github.com/ganyariya/go_monkey/ast"
 	"github.com/ganyariya/go_monkey/object"
@@ -26,8 +29,10 @@ func evalBlockStatements(stmts []ast.Statement, env *object.Environment) object.
 	for _, stmt := range stmts {
 		ret = Eval(stmt, env)
 		// BlockStatement では ReturnValue.Value にアンラップしない（ブロック文ネストでバグる)
+		// break / continue も同様にそれを囲むループまで伝搬させる
 		if ret != nil {
-			if ret.Type() == object.RETURN_VALUE_OBJ || ret.Type() == object.ERROR_OBJ {
+			switch ret.Type() {
+			case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
 				return ret
 			}
 		}
@@ -48,6 +53,9 @@ func evalLetStatement(stmt *ast.LetStatement, env *object.Environment) object.Ob
 	if isError(expObj) {
 		return expObj
 	}
+	if stmt.Pattern != nil {
+		return evalDestructuring(stmt.Pattern, expObj, env)
+	}
 	env.Set(stmt.Name.Value, expObj)
 	return expObj
 }
@@ -70,10 +78,27 @@ func evalIntegerLiteralExpression(exp *ast.IntegerLiteralExpression) object.Obje
 	return &object.Integer{Value: exp.Value}
 }
 
+func evalFloatLiteralExpression(exp *ast.FloatLiteralExpression) object.Object {
+	return &object.Float{Value: exp.Value}
+}
+
 func evalStringLiteralExpression(exp *ast.StringLiteralExpression) object.Object {
 	return &object.String{Value: exp.Value}
 }
 
+// 補間部分は評価した結果の Inspect() を埋め込む
+func evalInterpolatedStringExpression(exp *ast.InterpolatedStringExpression, env *object.Environment) object.Object {
+	var out strings.Builder
+	for _, part := range exp.Parts {
+		obj := Eval(part, env)
+		if isError(obj) {
+			return obj
+		}
+		out.WriteString(obj.Inspect())
+	}
+	return &object.String{Value: out.String()}
+}
+
 func evalIdentifierExpression(exp *ast.IdentifierExpression, env *object.Environment) object.Object {
 	if obj, ok := env.Get(exp.Value); ok {
 		return obj
@@ -82,7 +107,7 @@ func evalIdentifierExpression(exp *ast.IdentifierExpression, env *object.Environ
 	if builtin, ok := builtins[exp.Value]; ok {
 		return builtin
 	}
-	return newError(fmt.Sprintf("identifier not found: %s", exp.Value))
+	return newKindError(object.NAME_ERROR, "identifier not found: %s", exp.Value)
 }
 
 func evalPrefixExpression(exp *ast.PrefixExpression, env *object.Environment) object.Object {
@@ -90,13 +115,18 @@ func evalPrefixExpression(exp *ast.PrefixExpression, env *object.Environment) ob
 	if isError(rightObj) {
 		return rightObj
 	}
+	if result := applyRegisteredPrefixOperator(exp.Operator, rightObj, env); result != nil {
+		return result
+	}
 	switch exp.Operator {
 	case "!":
 		return evalBangPrefixOperator(rightObj)
 	case "-":
 		return evalMinusPrefixOperator(rightObj)
+	case "~":
+		return evalTildePrefixOperator(rightObj)
 	default:
-		return newError("unknown operator: %s%s", exp.Operator, rightObj.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s%s", exp.Operator, rightObj.Type())
 	}
 }
 
@@ -105,26 +135,224 @@ func evalInfixExpression(exp *ast.InfixExpression, env *object.Environment) obje
 	if isError(leftObj) {
 		return leftObj
 	}
+	if exp.Operator == "&&" || exp.Operator == "||" {
+		return evalLogicalExpression(exp.Operator, leftObj, exp.Right, env)
+	}
 	rightObj := Eval(exp.Right, env)
 	if isError(rightObj) {
 		return rightObj
 	}
+	if result := applyRegisteredInfixOperator(exp.Operator, leftObj, rightObj, env); result != nil {
+		return result
+	}
+	return evalInfixOperator(exp.Operator, leftObj, rightObj)
+}
+
+/*
+&& と || は短絡評価する
+結果を決めたオペランドをそのまま返す（`x && y` は x が偽なら x、そうでなければ y）
+*/
+func evalLogicalExpression(operator string, leftObj object.Object, right ast.Expression, env *object.Environment) object.Object {
+	if leftObj.AsBool() == (operator == "||") {
+		return leftObj
+	}
+	return Eval(right, env)
+}
+
+// 評価済みの左辺と右辺に中置演算子を適用する（複合代入 `x += 1` からも使う）
+func evalInfixOperator(operator string, leftObj, rightObj object.Object) object.Object {
 	switch {
+	// `is` は値ではなく同じオブジェクトかを判定する
+	case operator == "is":
+		return nativeBoolToBooleanObject(leftObj == rightObj)
 	// 整数は「値」で処理する
 	case leftObj.Type() == object.INTEGER_OBJ && rightObj.Type() == object.INTEGER_OBJ:
-		return evalIntegerInfixExpression(exp.Operator, leftObj, rightObj)
+		return evalIntegerInfixExpression(operator, leftObj, rightObj)
+	case isInteger(leftObj) && isInteger(rightObj):
+		return evalBigIntInfixExpression(operator, leftObj, rightObj)
+	// 片方が Float であれば Float に昇格して計算する
+	case isNumber(leftObj) && isNumber(rightObj):
+		return evalFloatInfixExpression(operator, leftObj, rightObj)
 	case leftObj.Type() == object.STRING_OBJ && rightObj.Type() == object.STRING_OBJ:
-		return evalStringInfixExpression(exp.Operator, leftObj, rightObj)
-	// reference (pointer) （異なる型 -> false）
-	case exp.Operator == "==":
-		return nativeBoolToBooleanObject(leftObj == rightObj)
-	case exp.Operator == "!=":
-		return nativeBoolToBooleanObject(leftObj != rightObj)
+		return evalStringInfixExpression(operator, leftObj, rightObj)
+	// 配列・ハッシュなどは構造で比較する（異なる型 -> false）
+	case operator == "==":
+		return nativeBoolToBooleanObject(objectsEqual(leftObj, rightObj))
+	case operator == "!=":
+		return nativeBoolToBooleanObject(!objectsEqual(leftObj, rightObj))
 	case leftObj.Type() != rightObj.Type():
-		return newError("type mismatch: %s %s %s", leftObj.Type(), exp.Operator, rightObj.Type())
+		return newKindError(object.TYPE_ERROR, "type mismatch: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", leftObj.Type(), operator, rightObj.Type())
+	}
+}
+
+/*
+代入式
+最も近い既存の束縛を書き換える。`x += v` は `x = x + v` と同じ演算を行う
+*/
+func evalAssignExpression(exp *ast.AssignExpression, env *object.Environment) object.Object {
+	switch exp.Target.(type) {
+	case *ast.IndexExpression, *ast.MemberExpression:
+		return evalIndexAssignExpression(exp, env)
+	}
+	ident, ok := exp.Target.(*ast.IdentifierExpression)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "cannot assign to %s", exp.Target.String())
+	}
+	current, declared := env.Get(ident.Value)
+	if !declared {
+		return newKindError(object.NAME_ERROR, "cannot assign to undeclared variable: %s", ident.Value)
+	}
+	value := Eval(exp.Value, env)
+	if isError(value) {
+		return value
+	}
+	if exp.Operator != "=" {
+		value = evalInfixOperator(strings.TrimSuffix(exp.Operator, "="), current, value)
+		if isError(value) {
+			return value
+		}
+	}
+	env.Assign(ident.Value, value)
+	return value
+}
+
+/*
+xs[i] = v や h["k"] = v （h.k = v） は配列・ハッシュをその場で書き換える
+配列とハッシュは参照として共有されるので、同じオブジェクトを指す変数やクロージャからも変更が見える
+（`let b = a; b[0] = 1;` は a も変える。push などの組み込み関数は従来どおり新しい配列を返す）
+*/
+func evalIndexAssignExpression(exp *ast.AssignExpression, env *object.Environment) object.Object {
+	var left, index object.Object
+	switch target := exp.Target.(type) {
+	case *ast.IndexExpression:
+		left = Eval(target.Left, env)
+		if isError(left) {
+			return left
+		}
+		index = Eval(target.Index, env)
+		if isError(index) {
+			return index
+		}
+	case *ast.MemberExpression:
+		left = Eval(target.Object, env)
+		if isError(left) {
+			return left
+		}
+		index = &object.String{Value: target.Property.Value}
+	}
+	value := Eval(exp.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		i, ok := index.(*object.Integer)
+		if !ok {
+			return newKindError(object.TYPE_ERROR, "array index must be INTEGER, got %s", index.Type())
+		}
+		idx := normalizeIndex(i.Value, len(left.Elements))
+		if idx < 0 {
+			return newKindError(object.INDEX_ERROR, "index out of range: %d (length %d)", i.Value, len(left.Elements))
+		}
+		if exp.Operator != "=" {
+			value = evalInfixOperator(strings.TrimSuffix(exp.Operator, "="), left.Elements[idx], value)
+			if isError(value) {
+				return value
+			}
+		}
+		left.Elements[idx] = value
+		return value
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newKindError(object.TYPE_ERROR, "unusable as hash key: %s", index.Type())
+		}
+		hashed := key.HashKey()
+		if exp.Operator != "=" {
+			pair, ok := left.Get(hashed)
+			if !ok {
+				return newKindError(object.KEY_ERROR, "key not found: %s", index.Inspect())
+			}
+			value = evalInfixOperator(strings.TrimSuffix(exp.Operator, "="), pair.Value, value)
+			if isError(value) {
+				return value
+			}
+		}
+		left.Set(hashed, object.HashPair{Key: index, Value: value})
+		return value
+	}
+	return newKindError(object.TYPE_ERROR, "index assignment not supported: %s", left.Type())
+}
+
+/*
+ループは Go の for で回すため、繰り返し回数が多くてもスタックを消費しない
+ループ文自体の値は NULL になる
+*/
+func evalWhileStatement(stmt *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(stmt.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !condition.AsBool() {
+			return NULL
+		}
+		if ret, exit := evalLoopBody(stmt.Body, env); exit {
+			return ret
+		}
+	}
+}
+
+// 繰り返しごとに新しい環境を作り、ループ変数を束縛する（クロージャがそれぞれの値を捕捉できる）
+func evalForStatement(stmt *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := Eval(stmt.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+	var elements []object.Object
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		elements = iterable.Elements
+	case *object.Hash:
+		for _, pair := range iterable.Pairs() {
+			elements = append(elements, pair.Key)
+		}
+	case *object.String:
+		for _, ch := range iterable.Chars() {
+			elements = append(elements, ch)
+		}
 	default:
-		return newError("unknown operator: %s %s %s", leftObj.Type(), exp.Operator, rightObj.Type())
+		err := newKindError(object.TYPE_ERROR, "cannot iterate over %s", iterable.Type())
+		err.Pos = stmt.Iterable.Pos()
+		return err
 	}
+
+	for _, element := range elements {
+		loopEnv := object.NewEnclosedEnvironment(env)
+		loopEnv.Set(stmt.Variable.Value, element)
+		if ret, exit := evalLoopBody(stmt.Body, loopEnv); exit {
+			return ret
+		}
+	}
+	return NULL
+}
+
+// ループ本体を評価する。ループを終了する場合は (ループの値, true) を返す
+func evalLoopBody(body *ast.BlockStatement, env *object.Environment) (object.Object, bool) {
+	ret := Eval(body, env)
+	if ret == nil {
+		return nil, false
+	}
+	switch ret.Type() {
+	case object.BREAK_OBJ:
+		return NULL, true
+	case object.RETURN_VALUE_OBJ, object.ERROR_OBJ:
+		return ret, true
+	}
+	return nil, false
 }
 
 func evalIfExpression(exp *ast.IfExpression, env *object.Environment) object.Object {
@@ -145,7 +373,14 @@ func evalIfExpression(exp *ast.IfExpression, env *object.Environment) object.Obj
 Function が「定義された」時点における Env を保持する（関数を実行するときに新しい EnclosedEnv をつくる）
 */
 func evalFunctionExpression(exp *ast.FunctionExpression, env *object.Environment) object.Object {
-	return &object.Function{Parameters: exp.Parameters, Body: exp.Body, Env: env}
+	return &object.Function{
+		Name:       exp.Name,
+		Parameters: exp.Parameters,
+		Defaults:   exp.Defaults,
+		Rest:       exp.Rest,
+		Body:       exp.Body,
+		Env:        env,
+	}
 }
 
 /*
@@ -162,6 +397,9 @@ func evalCallExpression(exp *ast.CallExpression, env *object.Environment) object
 		Function -> 関数を直接得る
 		Function は`定義時点`における env を保持する
 	*/
+	if member, ok := exp.Function.(*ast.MemberExpression); ok {
+		return evalMethodCallExpression(exp, member, env)
+	}
 	fnObj := Eval(exp.Function, env)
 	if isError(fnObj) {
 		return fnObj
@@ -170,7 +408,42 @@ func evalCallExpression(exp *ast.CallExpression, env *object.Environment) object
 	if len(args) == 1 && isError(args[0]) {
 		return args[0]
 	}
-	return applyCallFunction(fnObj, args)
+	return applyCallFunction(fnObj, args, exp, env)
+}
+
+/*
+obj.method(args) はハッシュ obj のキー "method" に格納された関数を呼び出す
+関数本体（とデフォルト値）からは obj を `self` として参照できる
+*/
+func evalMethodCallExpression(exp *ast.CallExpression, member *ast.MemberExpression, env *object.Environment) object.Object {
+	self := Eval(member.Object, env)
+	if isError(self) {
+		return self
+	}
+	hash, ok := self.(*object.Hash)
+	if !ok {
+		return newKindError(object.TYPE_ERROR, "cannot call method %s on %s", member.Property.Value, self.Type())
+	}
+	pair, ok := hash.Get((&object.String{Value: member.Property.Value}).HashKey())
+	if !ok {
+		return newKindError(object.KEY_ERROR, "undefined method %s", member.Property.Value)
+	}
+	args := evalExpressions(exp.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+	fn, ok := pair.Value.(*object.Function)
+	if !ok {
+		return applyCallFunction(pair.Value, args, exp, env)
+	}
+	// self だけを束縛した環境で関数の定義時の環境を包む（元の関数は書き換えない）
+	method := *fn
+	method.Env = object.NewEnclosedEnvironment(fn.Env)
+	method.Env.Set("self", hash)
+	if method.Name == "" {
+		method.Name = member.Property.Value
+	}
+	return applyCallFunction(&method, args, exp, env)
 }
 
 func evalArrayLiteralExpression(exp *ast.ArrayLiteralExpression, env *object.Environment) object.Object {
@@ -193,32 +466,45 @@ func evalIndexExpression(exp *ast.IndexExpression, env *object.Environment) obje
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return extractArrayByIndex(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return extractStringByIndex(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return extractHashByIndex(left, index)
 	default:
-		return newError("index operator not supported: %s", index.Type())
+		return newKindError(object.TYPE_ERROR, "index operator not supported: %s", index.Type())
 	}
 }
 
+// obj.field は obj["field"] と同じ（キーがなければ NULL）
+func evalMemberExpression(exp *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := Eval(exp.Object, env)
+	if isError(obj) {
+		return obj
+	}
+	if obj.Type() != object.HASH_OBJ {
+		return newKindError(object.TYPE_ERROR, "cannot access field %s on %s", exp.Property.Value, obj.Type())
+	}
+	return extractHashByIndex(obj, &object.String{Value: exp.Property.Value})
+}
+
 func evalHashLiteralexpression(exp *ast.HashLiteralExpression, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
-	for expKey, expValue := range exp.Pairs {
-		keyObj := Eval(expKey, env)
+	hash := object.NewHash()
+	for _, pair := range exp.Pairs {
+		keyObj := Eval(pair.Key, env)
 		if isError(keyObj) {
 			return keyObj
 		}
 		hashKeyObj, ok := keyObj.(object.Hashable)
 		if !ok {
-			return newError("unusable as hash key: %s", keyObj.Type())
+			return newKindError(object.TYPE_ERROR, "unusable as hash key: %s", keyObj.Type())
 		}
-		valueObj := Eval(expValue, env)
+		valueObj := Eval(pair.Value, env)
 		if isError(valueObj) {
 			return valueObj
 		}
-		hashed := hashKeyObj.HashKey()
-		pairs[hashed] = object.HashPair{Key: keyObj, Value: valueObj}
+		hash.Set(hashKeyObj.HashKey(), object.HashPair{Key: keyObj, Value: valueObj})
 	}
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
 
 // ------------------------------------------------------------------------------------------------------------
@@ -229,25 +515,81 @@ func evalBangPrefixOperator(right object.Object) object.Object {
 }
 
 func evalMinusPrefixOperator(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.Value == math.MinInt64 {
+			return object.NewInteger(new(big.Int).Neg(big.NewInt(right.Value)))
+		}
+		return &object.Integer{Value: -right.Value}
+	case *object.BigInt:
+		return object.NewInteger(new(big.Int).Neg(right.Value))
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: -%s", right.Type())
+	}
+}
+
+// ビット反転は整数のみ
+func evalTildePrefixOperator(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		return &object.Integer{Value: ^right.Value}
+	case *object.BigInt:
+		return object.NewInteger(new(big.Int).Not(right.Value))
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: ~%s", right.Type())
 	}
-	value := right.(*object.Integer).Value
-	return &object.Integer{Value: -value}
 }
 
+/*
+int64 の演算がオーバーフローする場合は BigInt に昇格して計算し直す
+0 による割り算・剰余はエラーにする
+*/
 func evalIntegerInfixExpression(operator string, left, right object.Object) object.Object {
 	leftValue := left.(*object.Integer).Value
 	rightValue := right.(*object.Integer).Value
 	switch operator {
 	case "+":
-		return &object.Integer{Value: leftValue + rightValue}
+		if value, ok := addInt64(leftValue, rightValue); ok {
+			return &object.Integer{Value: value}
+		}
+		return evalBigIntInfixExpression(operator, left, right)
 	case "-":
-		return &object.Integer{Value: leftValue - rightValue}
+		if value, ok := subInt64(leftValue, rightValue); ok {
+			return &object.Integer{Value: value}
+		}
+		return evalBigIntInfixExpression(operator, left, right)
 	case "*":
-		return &object.Integer{Value: leftValue * rightValue}
+		if value, ok := mulInt64(leftValue, rightValue); ok {
+			return &object.Integer{Value: value}
+		}
+		return evalBigIntInfixExpression(operator, left, right)
 	case "/":
+		if rightValue == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %d / %d", leftValue, rightValue)
+		}
+		// MinInt64 / -1 だけがオーバーフローする
+		if leftValue == math.MinInt64 && rightValue == -1 {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
 		return &object.Integer{Value: leftValue / rightValue}
+	case "%":
+		// 剰余の符号は Go と同じく左辺に従う（-7 % 3 == -1）
+		if rightValue == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %d %% %d", leftValue, rightValue)
+		}
+		return &object.Integer{Value: leftValue % rightValue}
+	case "&":
+		return &object.Integer{Value: leftValue & rightValue}
+	case "|":
+		return &object.Integer{Value: leftValue | rightValue}
+	case "^":
+		return &object.Integer{Value: leftValue ^ rightValue}
+	case "<<", ">>":
+		return evalIntegerShift(operator, left, right)
+	case "**":
+		return evalIntegerPower(left, right)
 	case "==":
 		return nativeBoolToBooleanObject(leftValue == rightValue)
 	case "!=":
@@ -256,8 +598,68 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 		return nativeBoolToBooleanObject(leftValue < rightValue)
 	case ">":
 		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// 左シフトで int64 からあふれる場合は BigInt に昇格する
+// 右シフトはシフト量が 64 以上なら Go と同じく 0（負数は -1）になる
+func evalIntegerShift(operator string, left, right object.Object) object.Object {
+	leftValue := left.(*object.Integer).Value
+	rightValue := right.(*object.Integer).Value
+	if rightValue < 0 {
+		return newKindError(object.VALUE_ERROR, "negative shift count: %d %s %d", leftValue, operator, rightValue)
+	}
+	if operator == "<<" {
+		if leftValue != 0 && (rightValue >= 63 || (leftValue<<rightValue)>>rightValue != leftValue) {
+			return evalBigIntInfixExpression(operator, left, right)
+		}
+		return &object.Integer{Value: leftValue << uint64(rightValue)}
+	}
+	return &object.Integer{Value: leftValue >> uint64(rightValue)}
+}
+
+func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
+	leftValue, _ := toFloat(left)
+	rightValue, _ := toFloat(right)
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftValue + rightValue}
+	case "-":
+		return &object.Float{Value: leftValue - rightValue}
+	case "*":
+		return &object.Float{Value: leftValue * rightValue}
+	case "/":
+		if rightValue == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %s / %s", left.Inspect(), right.Inspect())
+		}
+		return &object.Float{Value: leftValue / rightValue}
+	case "%":
+		if rightValue == 0 {
+			return newKindError(object.ZERO_DIVISION_ERROR, "division by zero: %s %% %s", left.Inspect(), right.Inspect())
+		}
+		return &object.Float{Value: math.Mod(leftValue, rightValue)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftValue, rightValue)}
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
+	default:
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
@@ -271,27 +673,122 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 		return nativeBoolToBooleanObject(leftValue == rightValue)
 	case "!=":
 		return nativeBoolToBooleanObject(leftValue != rightValue)
+	// 辞書順（コードポイント順）で比較する
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newKindError(object.TYPE_ERROR, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
+// 負の添字は末尾から数える（xs[-1] は最後の要素）。範囲外は NULL
 func extractArrayByIndex(arr, index object.Object) object.Object {
 	arrObj := arr.(*object.Array)
-	idx := index.(*object.Integer).Value
-	if idx < 0 || int(idx) >= len(arrObj.Elements) {
+	idx := normalizeIndex(index.(*object.Integer).Value, len(arrObj.Elements))
+	if idx < 0 || idx >= len(arrObj.Elements) {
 		return NULL
 	}
 	return arrObj.Elements[idx]
 }
 
+// 文字列はルーン単位で添字アクセスする
+func extractStringByIndex(str, index object.Object) object.Object {
+	strObj := str.(*object.String)
+	idx := normalizeIndex(index.(*object.Integer).Value, strObj.Len())
+	ch, ok := strObj.At(idx)
+	if !ok {
+		return NULL
+	}
+	return ch
+}
+
+// 負の添字を先頭からの添字に直す（範囲外なら -1）
+func normalizeIndex(idx int64, length int) int {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return -1
+	}
+	return int(idx)
+}
+
+/*
+xs[start:end] は新しい配列（文字列）を返す
+Python と同様に範囲外の添字は両端に切り詰め、start が end 以降なら空になる
+*/
+func evalSliceExpression(exp *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(exp.Left, env)
+	if isError(left) {
+		return left
+	}
+	bounds := []object.Object{}
+	for _, e := range []ast.Expression{exp.Start, exp.End} {
+		if e == nil {
+			bounds = append(bounds, NULL)
+			continue
+		}
+		obj := Eval(e, env)
+		if isError(obj) {
+			return obj
+		}
+		if obj.Type() != object.INTEGER_OBJ {
+			return newKindError(object.TYPE_ERROR, "slice index must be INTEGER, got %s", obj.Type())
+		}
+		bounds = append(bounds, obj)
+	}
+	switch left := left.(type) {
+	case *object.Array:
+		start, end := sliceBounds(bounds[0], bounds[1], len(left.Elements))
+		elements := make([]object.Object, end-start)
+		copy(elements, left.Elements[start:end])
+		return &object.Array{Elements: elements}
+	case *object.String:
+		start, end := sliceBounds(bounds[0], bounds[1], left.Len())
+		return left.Slice(start, end)
+	}
+	return newKindError(object.TYPE_ERROR, "slice operator not supported: %s", left.Type())
+}
+
+// 省略された添字（NULL）と負の添字を [0, length] の範囲に直す
+func sliceBounds(startObj, endObj object.Object, length int) (int, int) {
+	clamp := func(obj object.Object, fallback int) int {
+		i, ok := obj.(*object.Integer)
+		if !ok {
+			return fallback
+		}
+		idx := i.Value
+		if idx < 0 {
+			idx += int64(length)
+		}
+		if idx < 0 {
+			return 0
+		}
+		if idx > int64(length) {
+			return length
+		}
+		return int(idx)
+	}
+	start, end := clamp(startObj, 0), clamp(endObj, length)
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
 func extractHashByIndex(hash, index object.Object) object.Object {
 	hashObj := hash.(*object.Hash)
 	key, ok := index.(object.Hashable)
 	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
+		return newKindError(object.TYPE_ERROR, "unusable as hash key: %s", index.Type())
 	}
-	pair, ok := hashObj.Pairs[key.HashKey()]
+	pair, ok := hashObj.Get(key.HashKey())
 	if !ok {
 		// ハッシュキーに対応する値がない
 		return NULL
@@ -318,35 +815,185 @@ func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Ob
 	return ret
 }
 
+// 関数呼び出しの深さの上限。無限再帰は Go のスタックを使い果たす前に RecursionError になる
+const maxCallDepth = 10000
+
 /*
 評価済みの arg objects を function object に与えて関数式を評価する。
+call は呼び出し位置（スタックトレースに使う）、env は呼び出し元の環境（呼び出しの深さを数える）
+関数の本体でエラーが起きたときは、エラーが呼び出し元に戻るたびにその呼び出しを Error.Stack に積む
+（引数の数の誤りのように本体に入る前のエラーは呼び出し元のエラーとして扱う）
 */
-func applyCallFunction(fn object.Object, args []object.Object) object.Object {
+func applyCallFunction(fn object.Object, args []object.Object, call *ast.CallExpression, env *object.Environment) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		registeredEnv := registerEnclosedCallEnv(fn, args)
+		if env.Depth() >= maxCallDepth {
+			return newKindError(object.RECURSION_ERROR, "maximum call depth exceeded (%d)", maxCallDepth)
+		}
+		registeredEnv, errObj := registerEnclosedCallEnv(fn, args, env.Depth()+1)
+		if errObj != nil {
+			return errObj
+		}
 		evaluated := Eval(fn.Body, registeredEnv)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			frame := object.StackFrame{Function: fn.Name, Pos: call.Function.Pos(), Args: summarizeArgs(args)}
+			errObj.Stack = append(errObj.Stack, frame)
+		}
 		/* Unwrap しないと return 効果が関数をまたいで浮上して実行が途中で停止してしまう */
 		return unwrapReturnValue(evaluated)
 	case *object.Builtin:
 		return fn.Fn(args...)
 	default:
-		return newError("not a function: %s", fn.Type())
+		return newKindError(object.TYPE_ERROR, "not a function: %s", fn.Type())
 	}
 }
 
+// スタックトレースに表示する実引数の要約（長い値は省略する）
+func summarizeArgs(args []object.Object) string {
+	const maxLen = 20
+	summaries := []string{}
+	for _, arg := range args {
+		// 大きな配列・ハッシュを毎回すべて文字列にしないよう、maxLen を超えた時点で打ち切る
+		w := &summaryWriter{limit: maxLen + 1}
+		switch arg := arg.(type) {
+		case *object.String:
+			w.write(strconv.Quote(truncateRunes(arg.Value, maxLen)))
+		default:
+			w.writeObject(arg)
+		}
+		s := string(w.runes)
+		if len(w.runes) > maxLen {
+			s = string(w.runes[:maxLen-3]) + "..."
+		}
+		summaries = append(summaries, s)
+	}
+	return strings.Join(summaries, ", ")
+}
+
+// limit 文字まで書き込んだら以降の書き込みを捨てる
+type summaryWriter struct {
+	runes []rune
+	limit int
+}
+
+func (w *summaryWriter) full() bool { return len(w.runes) >= w.limit }
+
+func (w *summaryWriter) write(s string) {
+	for _, r := range s {
+		if w.full() {
+			return
+		}
+		w.runes = append(w.runes, r)
+	}
+}
+
+// Inspect と同じ表記を、必要な分だけ書き込む
+func (w *summaryWriter) writeObject(obj object.Object) {
+	if w.full() {
+		return
+	}
+	switch obj := obj.(type) {
+	case *object.String:
+		w.write(truncateRunes(obj.Value, w.limit))
+	case *object.Function:
+		w.write("fn")
+		if obj.Name != "" {
+			w.write(" " + obj.Name)
+		}
+	case *object.Array:
+		w.write("[")
+		for i, e := range obj.Elements {
+			if w.full() {
+				return
+			}
+			if i > 0 {
+				w.write(", ")
+			}
+			w.writeObject(e)
+		}
+		w.write("]")
+	case *object.Hash:
+		w.write("{")
+		first := true
+		obj.Range(func(pair object.HashPair) bool {
+			if !first {
+				w.write(", ")
+			}
+			first = false
+			w.writeObject(pair.Key)
+			w.write(": ")
+			w.writeObject(pair.Value)
+			return !w.full()
+		})
+		w.write("}")
+	default:
+		w.write(obj.Inspect())
+	}
+}
+
+func truncateRunes(s string, n int) string {
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
 /*
 仮引数（変数）と実引数（実値）を紐付けた 新たな記憶容量 Environment を返す
 **Function Object が持つ親環境に 新しい環境はラップされる**
 */
-func registerEnclosedCallEnv(fnObj *object.Function, args []object.Object) *object.Environment {
-	enclosedEnv := object.NewEnclosedEnvironment(fnObj.Env)
+func registerEnclosedCallEnv(fnObj *object.Function, args []object.Object, depth int) (*object.Environment, object.Object) {
+	if errObj := checkArity(fnObj, len(args)); errObj != nil {
+		return nil, errObj
+	}
+	enclosedEnv := object.NewCallEnvironment(fnObj.Env, depth)
 	// Parameters = 仮引数[x, y, z]  args = 評価済実引数[10, 1, 4]
-	for i := 0; i < len(fnObj.Parameters); i++ {
-		// 変数に値を登録する (x = 10)
-		enclosedEnv.Set(fnObj.Parameters[i].Value, args[i])
+	for i, param := range fnObj.Parameters {
+		if i < len(args) {
+			// 変数に値を登録する (x = 10)
+			enclosedEnv.Set(param.Value, args[i])
+			continue
+		}
+		// 実引数が省略された仮引数はデフォルト値を評価する（前の仮引数を参照できる `fn(x, y = x * 2)`）
+		value := Eval(fnObj.Defaults[param.Value], enclosedEnv)
+		if isError(value) {
+			return nil, value
+		}
+		enclosedEnv.Set(param.Value, value)
 	}
-	return enclosedEnv
+	// 残余引数は余った実引数を配列にまとめる
+	if fnObj.Rest != nil {
+		rest := []object.Object{}
+		if len(args) > len(fnObj.Parameters) {
+			rest = append(rest, args[len(fnObj.Parameters):]...)
+		}
+		enclosedEnv.Set(fnObj.Rest.Value, &object.Array{Elements: rest})
+	}
+	return enclosedEnv, nil
+}
+
+// 実引数の数が仮引数と合っているか調べる（デフォルト値をもつ仮引数は省略でき、残余引数があれば上限はない）
+func checkArity(fnObj *object.Function, got int) *object.Error {
+	max := len(fnObj.Parameters)
+	min := max - len(fnObj.Defaults)
+	name := "anonymous function"
+	if fnObj.Name != "" {
+		name = "`" + fnObj.Name + "`"
+	}
+	switch {
+	case got < min && fnObj.Rest != nil:
+		return newKindError(object.ARGUMENT_ERROR, "wrong number of arguments to %s. expected at least %d, got=%d", name, min, got)
+	case fnObj.Rest != nil:
+		return nil
+	case (got < min || got > max) && min == max:
+		return newKindError(object.ARGUMENT_ERROR, "wrong number of arguments to %s. expected=%d, got=%d", name, min, got)
+	case got < min || got > max:
+		return newKindError(object.ARGUMENT_ERROR, "wrong number of arguments to %s. expected %d to %d, got=%d", name, min, max, got)
+	}
+	return nil
 }
 
 // ------------------------------------------------------------------------------------------------------------
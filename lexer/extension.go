@@ -0,0 +1,67 @@
+package lexer
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ganyariya/go_monkey/token"
+)
+
+// 利用者が追加した演算子トークン（`=~` `..` など）
+type operator struct {
+	literal   string
+	tokenType token.TokenType
+}
+
+/*
+独自のトークンを登録する
+- 識別子として読める literal（`in` `mod` など）はキーワードとして扱う
+- それ以外は演算子として、組み込みのトークンよりも優先して最長一致で読む（`..` と `...` を両方登録できる）
+*/
+func (l *Lexer) RegisterToken(literal string, tokenType token.TokenType) {
+	if literal == "" {
+		return
+	}
+	if isIdentifierLiteral(literal) {
+		if l.keywords == nil {
+			l.keywords = map[string]token.TokenType{}
+		}
+		l.keywords[literal] = tokenType
+		return
+	}
+	l.operators = append(l.operators, operator{literal: literal, tokenType: tokenType})
+	sort.SliceStable(l.operators, func(i, j int) bool {
+		return len(l.operators[i].literal) > len(l.operators[j].literal)
+	})
+}
+
+// ch から始まる登録済みの演算子を読む。終了時には ch は演算子の次の文字を指す
+func (l *Lexer) readRegisteredOperator() (token.Token, bool) {
+	for _, op := range l.operators {
+		if !l.hasPrefix(op.literal) {
+			continue
+		}
+		for i := 0; i < utf8.RuneCountInString(op.literal); i++ {
+			l.readChar()
+		}
+		return token.Token{Type: op.tokenType, Literal: op.literal}, true
+	}
+	return token.Token{}, false
+}
+
+// 登録済みのキーワードを優先して識別子のトークンタイプを調べる
+func (l *Lexer) lookupIdentifier(identifier string) token.TokenType {
+	if tokenType, ok := l.keywords[identifier]; ok {
+		return tokenType
+	}
+	return token.LookupIdentifier(identifier)
+}
+
+func isIdentifierLiteral(s string) bool {
+	for _, r := range s {
+		if !isIdentifierLetter(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,17 +1,43 @@
 package lexer
 
-import "github.com/ganyariya/go_monkey/token"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
-// レキサー
+	"github.com/ganyariya/go_monkey/token"
+)
+
+/*
+レキサー
+入力は UTF-8 として 1 ルーンずつ読み進める（position / readPosition はバイトオフセット）
+*/
 type Lexer struct {
 	input        string
 	position     int  // 常に、現在 ch に入っている文字の位置を指す
 	readPosition int  // 常に、これから読もうとしている次の文字の位置を指す
-	ch           byte // 現在検査中の文字
+	ch           rune // 現在検査中の文字
+
+	filename string // エラーメッセージに表示するファイル名
+	line     int    // ch がある行 (1 始まり)
+	column   int    // ch がある列 (1 始まり)
+
+	errors         []*Error        // 閉じられていない文字列などの字句エラー
+	interpolations []interpolation // 読み込み中の文字列補間 `${ ... }` のスタック
+
+	// RegisterToken で追加されたトークン
+	operators []operator // 長い順に並べる
+	keywords  map[string]token.TokenType
 }
 
 func NewLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+	return NewLexerWithFilename("", input)
+}
+
+// ファイル名つきのレキサーを作る（トークンの位置情報にファイル名が入る）
+func NewLexerWithFilename(filename, input string) *Lexer {
+	l := &Lexer{input: input, filename: filename, line: 1}
 	l.readChar()
 	return l
 }
@@ -20,11 +46,19 @@ func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
 	l.skipWhitespace()
+	pos := l.currentPosition()
+
+	if tok, ok := l.readRegisteredOperator(); ok {
+		tok.Pos = pos
+		return tok
+	}
 
 	switch l.ch {
 	case '=':
 		if l.isTwoCharToken('=', '=') {
 			tok = token.Token{Type: token.EQ, Literal: l.readTwoCharToken()}
+		} else if l.isTwoCharToken('=', '>') {
+			tok = token.Token{Type: token.FAT_ARROW, Literal: l.readTwoCharToken()}
 		} else {
 			tok = token.NewToken(token.ASSIGN, '=')
 		}
@@ -35,24 +69,90 @@ func (l *Lexer) NextToken() token.Token {
 			tok = token.NewToken(token.BANG, '!')
 		}
 	case '+':
-		tok = token.NewToken(token.PLUS, '+')
+		if l.isTwoCharToken('+', '=') {
+			tok = token.Token{Type: token.PLUS_ASSIGN, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.PLUS, '+')
+		}
 	case '-':
-		tok = token.NewToken(token.MINUS, '-')
+		if l.isTwoCharToken('-', '=') {
+			tok = token.Token{Type: token.MINUS_ASSIGN, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.MINUS, '-')
+		}
 	case '*':
-		tok = token.NewToken(token.ASTERISK, '*')
+		if l.isTwoCharToken('*', '=') {
+			tok = token.Token{Type: token.ASTERISK_ASSIGN, Literal: l.readTwoCharToken()}
+		} else if l.isTwoCharToken('*', '*') {
+			tok = token.Token{Type: token.POWER, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.ASTERISK, '*')
+		}
+	case '%':
+		tok = token.NewToken(token.PERCENT, '%')
+	case '&':
+		if l.isTwoCharToken('&', '&') {
+			tok = token.Token{Type: token.AND, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.AMPERSAND, '&')
+		}
+	case '|':
+		if l.isTwoCharToken('|', '|') {
+			tok = token.Token{Type: token.OR, Literal: l.readTwoCharToken()}
+		} else if l.isTwoCharToken('|', '>') {
+			tok = token.Token{Type: token.PIPELINE, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.PIPE, '|')
+		}
+	case '^':
+		tok = token.NewToken(token.CARET, '^')
+	case '~':
+		tok = token.NewToken(token.TILDE, '~')
 	case '/':
-		tok = token.NewToken(token.SLASH, '/')
+		if l.isTwoCharToken('/', '/') {
+			tok = token.Token{Type: token.COMMENT, Literal: l.readLineComment()}
+		} else if l.isTwoCharToken('/', '*') {
+			tok = token.Token{Type: token.COMMENT, Literal: l.readBlockComment(pos)}
+		} else if l.isTwoCharToken('/', '=') {
+			tok = token.Token{Type: token.SLASH_ASSIGN, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.SLASH, '/')
+		}
 	case '<':
-		tok = token.NewToken(token.LT, '<')
+		if l.isTwoCharToken('<', '<') {
+			tok = token.Token{Type: token.LSHIFT, Literal: l.readTwoCharToken()}
+		} else if l.isTwoCharToken('<', '=') {
+			tok = token.Token{Type: token.LT_EQ, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.LT, '<')
+		}
 	case '>':
-		tok = token.NewToken(token.GT, '>')
+		if l.isTwoCharToken('>', '>') {
+			tok = token.Token{Type: token.RSHIFT, Literal: l.readTwoCharToken()}
+		} else if l.isTwoCharToken('>', '=') {
+			tok = token.Token{Type: token.GT_EQ, Literal: l.readTwoCharToken()}
+		} else {
+			tok = token.NewToken(token.GT, '>')
+		}
 	case '(':
 		tok = token.NewToken(token.LPAREN, '(')
 	case ')':
 		tok = token.NewToken(token.RPAREN, ')')
 	case '{':
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1].depth++
+		}
 		tok = token.NewToken(token.LBRACE, '{')
 	case '}':
+		// `${ ... }` を閉じる `}` であれば文字列の続きを読む
+		if n := len(l.interpolations); n > 0 && l.interpolations[n-1].depth == 0 {
+			heredoc := l.interpolations[n-1].heredoc
+			l.interpolations = l.interpolations[:n-1]
+			tok = l.readStringPart(pos, heredoc, false)
+			break
+		} else if n > 0 {
+			l.interpolations[n-1].depth--
+		}
 		tok = token.NewToken(token.RBRACE, '}')
 	case '[':
 		tok = token.NewToken(token.LBRACKET, '[')
@@ -64,10 +164,24 @@ func (l *Lexer) NextToken() token.Token {
 		tok = token.NewToken(token.SEMICOLON, ';')
 	case ':':
 		tok = token.NewToken(token.COLON, ':')
+	case '.':
+		if l.hasPrefix("...") {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else {
+			tok = token.NewToken(token.DOT, '.')
+		}
 	case '"':
+		tok = l.readStringPart(pos, l.hasPrefix(`"""`), true)
+	case '`':
 		tok.Type = token.STRING
-		tok.Literal = l.readString()
+		tok.Literal = l.readRawString(pos)
 	case 0:
+		if len(l.interpolations) > 0 {
+			l.interpolations = nil
+			l.appendError(pos, "unterminated string interpolation")
+		}
 		tok.Literal = ""
 		tok.Type = token.EOF
 	default: // 識別子・キーワード・数について
@@ -75,11 +189,12 @@ func (l *Lexer) NextToken() token.Token {
 		if isIdentifierLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			// リテラルから「変数」か「Keyword」か調べる
-			tok.Type = token.LookupIdentifier(tok.Literal)
+			tok.Type = l.lookupIdentifier(tok.Literal)
+			tok.Pos = pos
 			return tok
 		} else if isDigit(l.ch) {
-			tok.Type = token.INT
-			tok.Literal = l.readNumber()
+			tok.Literal, tok.Type = l.readNumber()
+			tok.Pos = pos
 			return tok
 		} else {
 			// 失敗したら ILLEGAL トークンを埋め込むことで、テストなどでエラーを発見しやすくする
@@ -88,27 +203,41 @@ func (l *Lexer) NextToken() token.Token {
 	}
 
 	l.readChar()
+	tok.Pos = pos
 	return tok
 }
 
 // 次の一文字を読む & 現在位置を進める
 // => l.ch が更新される
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	width := 0
 	if l.readPosition >= len(l.input) {
 		l.ch = 0 // ASCII "NUL" に対応している
 	} else {
-		l.ch = l.input[l.readPosition]
+		// 不正な UTF-8 バイト列は utf8.RuneError (width = 1) になり ILLEGAL トークンとなる
+		l.ch, width = utf8.DecodeRuneInString(l.input[l.readPosition:])
 	}
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
+	l.column++
+}
+
+// 現在の文字 ch の位置
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{Filename: l.filename, Offset: l.position, Line: l.line, Column: l.column}
 }
 
 // 次の一文字を先読みする
-func (l *Lexer) peekChar() byte {
+func (l *Lexer) peekChar() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
-	return l.input[l.readPosition]
+	r, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+	return r
 }
 
 // 変数の識別子を取得する
@@ -120,26 +249,92 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[p:l.position]
 }
 
-func (l *Lexer) readNumber() string {
+/*
+整数 `123` か浮動小数点数 `3.14` `1e-9` `2.5E+3` を読む
+`.` や `e` の後ろに数字が続かない場合は数の一部とみなさない
+
+整数は `0xFF` `0o17` `0b1010` の基数接頭辞と、桁区切りの `_`（`1_000_000`）に対応する
+桁の妥当性（`0b12` や `1__0` など）は構文解析器の strconv で検査する
+*/
+func (l *Lexer) readNumber() (string, token.TokenType) {
 	p := l.position
-	for isDigit(l.ch) {
+	var tokenType token.TokenType = token.INT
+	if l.ch == '0' && isBasePrefix(l.peekChar()) {
 		l.readChar()
+		l.readChar()
+		for isHexDigit(l.ch) || l.ch == '_' {
+			l.readChar()
+		}
+		return l.input[p:l.position], tokenType
 	}
-	return l.input[p:l.position]
+	l.readDigits()
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		tokenType = token.FLOAT
+		l.readChar()
+		l.readDigits()
+	}
+	if l.isExponentStart() {
+		tokenType = token.FLOAT
+		l.readChar()
+		if l.ch == '+' || l.ch == '-' {
+			l.readChar()
+		}
+		l.readDigits()
+	}
+	return l.input[p:l.position], tokenType
+}
+
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) || l.ch == '_' {
+		l.readChar()
+	}
+}
+
+// `e10` `E-3` のような指数部の開始か
+func (l *Lexer) isExponentStart() bool {
+	if l.ch != 'e' && l.ch != 'E' {
+		return false
+	}
+	rest := l.input[l.readPosition:]
+	if len(rest) > 0 && (rest[0] == '+' || rest[0] == '-') {
+		rest = rest[1:]
+	}
+	return len(rest) > 0 && isDigit(rune(rest[0]))
 }
 
-func (l *Lexer) readString() string {
-	p := l.position + 1
+// `//` から行末（改行は含まない）までを読む
+func (l *Lexer) readLineComment() string {
+	p := l.position
+	for l.peekChar() != '\n' && l.peekChar() != 0 {
+		l.readChar()
+	}
+	return l.input[p:l.readPosition]
+}
+
+// `/*` から `*/` までを読む（入れ子には対応しない）
+func (l *Lexer) readBlockComment(pos token.Position) string {
+	p := l.position
+	l.readChar()
 	for {
 		l.readChar()
-		if l.ch == '"' || l.ch == 0 {
+		if l.ch == '*' && l.peekChar() == '/' {
+			l.readChar()
+			break
+		}
+		if l.ch == 0 {
+			l.appendError(pos, "unterminated block comment")
 			break
 		}
 	}
-	return l.input[p:l.position]
+	return l.input[p:l.readPosition]
+}
+
+// 現在の文字 ch から始まる入力が s で始まっているか
+func (l *Lexer) hasPrefix(s string) bool {
+	return strings.HasPrefix(l.input[l.position:], s)
 }
 
-func (l *Lexer) isTwoCharToken(c1, c2 byte) bool {
+func (l *Lexer) isTwoCharToken(c1, c2 rune) bool {
 	return l.ch == c1 && l.peekChar() == c2
 }
 
@@ -157,11 +352,33 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
-// 変数の識別子として利用できる文字
-func isIdentifierLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+// 字句エラー
+type Error struct {
+	Pos     token.Position
+	Message string
 }
 
-func isDigit(ch byte) bool {
+// `file:line:col: message` の形式
+func (e *Error) Error() string { return fmt.Sprintf("%s: %s", e.Pos, e.Message) }
+
+func (l *Lexer) Errors() []*Error {
+	return l.errors
+}
+
+func (l *Lexer) appendError(pos token.Position, msg string) {
+	l.errors = append(l.errors, &Error{Pos: pos, Message: msg})
+}
+
+// 変数の識別子として利用できる文字（日本語などの Unicode の文字も含む）
+func isIdentifierLetter(ch rune) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_' || ch >= utf8.RuneSelf && unicode.IsLetter(ch)
+}
+
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
+
+// 0x 0o 0b の 2 文字目
+func isBasePrefix(ch rune) bool {
+	return ch == 'x' || ch == 'X' || ch == 'o' || ch == 'O' || ch == 'b' || ch == 'B'
+}
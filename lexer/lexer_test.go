@@ -14,7 +14,7 @@ let add = fn(x, y){
 	x + y;
 };
 let result = add(five, ten);
-!-/*5;
+!-/ *5;
 5 < 10 > 5;
 
 if (5 < 10) {
@@ -160,3 +160,476 @@ macro(x, y) {x + y;};
 	}
 
 }
+
+func TestNextTokenPosition(t *testing.T) {
+	input := `let x = 5;
+  x + 10;
+`
+	tests := []struct {
+		expectedLiteral string
+		expectedLine    int
+		expectedColumn  int
+		expectedOffset  int
+	}{
+		{"let", 1, 1, 0},
+		{"x", 1, 5, 4},
+		{"=", 1, 7, 6},
+		{"5", 1, 9, 8},
+		{";", 1, 10, 9},
+		{"x", 2, 3, 13},
+		{"+", 2, 5, 15},
+		{"10", 2, 7, 17},
+		{";", 2, 9, 19},
+		{"", 3, 1, 21},
+	}
+
+	l := NewLexerWithFilename("main.monkey", input)
+
+	for i, tt := range tests {
+		nToken := l.NextToken()
+
+		if nToken.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Literal Wrong. expected=%q, got=%q", i, tt.expectedLiteral, nToken.Literal)
+		}
+		pos := nToken.Pos
+		if pos.Filename != "main.monkey" || pos.Line != tt.expectedLine || pos.Column != tt.expectedColumn || pos.Offset != tt.expectedOffset {
+			t.Fatalf("tests[%d] - Position Wrong. expected=main.monkey:%d:%d(offset=%d), got=%s(offset=%d)",
+				i, tt.expectedLine, tt.expectedColumn, tt.expectedOffset, pos, pos.Offset)
+		}
+	}
+}
+
+func TestNextTokenUnicode(t *testing.T) {
+	input := `let 挨拶 = "こんにちは、世界";
+挨拶 + "🐒";
+é_x ☃`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedColumn  int
+	}{
+		{token.LET, "let", 1},
+		{token.IDENTIFIER, "挨拶", 5},
+		{token.ASSIGN, "=", 8},
+		{token.STRING, "こんにちは、世界", 10},
+		{token.SEMICOLON, ";", 20},
+		{token.IDENTIFIER, "挨拶", 1},
+		{token.PLUS, "+", 4},
+		{token.STRING, "🐒", 6},
+		{token.SEMICOLON, ";", 9},
+		{token.IDENTIFIER, "é_x", 1},
+		{token.ILLEGAL, "☃", 5},
+		{token.EOF, "", 6},
+	}
+
+	l := NewLexer(input)
+
+	for i, tt := range tests {
+		nToken := l.NextToken()
+
+		if nToken.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - Token:%v  TokenType Wrong. expected=%q, got=%q", i, nToken, tt.expectedType, nToken.Type)
+		}
+		if nToken.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Token:%v  Literal Wrong. expected=%q, got=%q", i, nToken, tt.expectedLiteral, nToken.Literal)
+		}
+		if nToken.Pos.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - Token:%v  Column Wrong. expected=%d, got=%d", i, nToken, tt.expectedColumn, nToken.Pos.Column)
+		}
+	}
+}
+
+func TestNextTokenStrings(t *testing.T) {
+	input := "\"a\\tb\\n\\\"c\\\"\\\\ \\$ \\u{1F412}\";\n" +
+		"`raw\\n ${x}\n`;\n" +
+		"\"hello ${name}, ${ {\"k\": \"v\"}[\"k\"] }!\";\n" +
+		"\"\"\"\n  line \"1\"\n  ${n} line 2\n\"\"\";\n" +
+		"\"${x}\";"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.STRING, "a\tb\n\"c\"\\ $ 🐒"},
+		{token.SEMICOLON, ";"},
+		{token.STRING, "raw\\n ${x}\n"},
+		{token.SEMICOLON, ";"},
+		{token.STRING_HEAD, "hello "},
+		{token.IDENTIFIER, "name"},
+		{token.STRING_MIDDLE, ", "},
+		{token.LBRACE, "{"},
+		{token.STRING, "k"},
+		{token.COLON, ":"},
+		{token.STRING, "v"},
+		{token.RBRACE, "}"},
+		{token.LBRACKET, "["},
+		{token.STRING, "k"},
+		{token.RBRACKET, "]"},
+		{token.STRING_TAIL, "!"},
+		{token.SEMICOLON, ";"},
+		{token.STRING_HEAD, "  line \"1\"\n  "},
+		{token.IDENTIFIER, "n"},
+		{token.STRING_TAIL, " line 2\n"},
+		{token.SEMICOLON, ";"},
+		{token.STRING_HEAD, ""},
+		{token.IDENTIFIER, "x"},
+		{token.STRING_TAIL, ""},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+
+	for i, tt := range tests {
+		nToken := l.NextToken()
+
+		if nToken.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - Token:%v  TokenType Wrong. expected=%q, got=%q", i, nToken, tt.expectedType, nToken.Type)
+		}
+		if nToken.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Token:%v  Literal Wrong. expected=%q, got=%q", i, nToken, tt.expectedLiteral, nToken.Literal)
+		}
+	}
+	if len(l.Errors()) != 0 {
+		t.Fatalf("unexpected lexer errors: %v", l.Errors())
+	}
+}
+
+func TestLexerStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"abc`, "1:1: unterminated string literal"},
+		{"let s = `abc", "1:9: unterminated raw string literal"},
+		{`"a\qb"`, `1:3: unknown escape sequence: \q`},
+		{`"\u{zz}"`, `1:2: invalid unicode escape sequence (expected \u{XXXX})`},
+		{`"a ${x`, "1:7: unterminated string interpolation"},
+	}
+
+	for _, tt := range tests {
+		l := NewLexer(tt.input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+		if len(l.Errors()) != 1 {
+			t.Fatalf("expected 1 error for %q. got=%v", tt.input, l.Errors())
+		}
+		if l.Errors()[0].Error() != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expected, l.Errors()[0].Error())
+		}
+	}
+}
+
+func TestNextTokenComments(t *testing.T) {
+	input := `// line comment
+let x = 10 / 2; // trailing
+/* block
+   comment */ x`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.COMMENT, "// line comment"},
+		{token.LET, "let"},
+		{token.IDENTIFIER, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "10"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.COMMENT, "// trailing"},
+		{token.COMMENT, "/* block\n   comment */"},
+		{token.IDENTIFIER, "x"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+
+	for i, tt := range tests {
+		nToken := l.NextToken()
+
+		if nToken.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - Token:%v  TokenType Wrong. expected=%q, got=%q", i, nToken, tt.expectedType, nToken.Type)
+		}
+		if nToken.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Token:%v  Literal Wrong. expected=%q, got=%q", i, nToken, tt.expectedLiteral, nToken.Literal)
+		}
+	}
+}
+
+func TestNextTokenNumbers(t *testing.T) {
+	input := `3.14 1e-9 2.5E+3 10 1.x 7e`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "1e-9"},
+		{token.FLOAT, "2.5E+3"},
+		{token.INT, "10"},
+		{token.INT, "1"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "x"},
+		{token.INT, "7"},
+		{token.IDENTIFIER, "e"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+
+	for i, tt := range tests {
+		nToken := l.NextToken()
+
+		if nToken.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - Token:%v  TokenType Wrong. expected=%q, got=%q", i, nToken, tt.expectedType, nToken.Type)
+		}
+		if nToken.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Token:%v  Literal Wrong. expected=%q, got=%q", i, nToken, tt.expectedLiteral, nToken.Literal)
+		}
+	}
+}
+
+func TestNextTokenIntegerLiteralsAndBitwise(t *testing.T) {
+	input := `0xFF 0o17 0b1010 1_000_000 1_000.5
+a & b | c ^ ~d << 2 >> 1 % 3`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "0xFF"},
+		{token.INT, "0o17"},
+		{token.INT, "0b1010"},
+		{token.INT, "1_000_000"},
+		{token.FLOAT, "1_000.5"},
+		{token.IDENTIFIER, "a"},
+		{token.AMPERSAND, "&"},
+		{token.IDENTIFIER, "b"},
+		{token.PIPE, "|"},
+		{token.IDENTIFIER, "c"},
+		{token.CARET, "^"},
+		{token.TILDE, "~"},
+		{token.IDENTIFIER, "d"},
+		{token.LSHIFT, "<<"},
+		{token.INT, "2"},
+		{token.RSHIFT, ">>"},
+		{token.INT, "1"},
+		{token.PERCENT, "%"},
+		{token.INT, "3"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+
+	for i, tt := range tests {
+		nToken := l.NextToken()
+
+		if nToken.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - Token:%v  TokenType Wrong. expected=%q, got=%q", i, nToken, tt.expectedType, nToken.Type)
+		}
+		if nToken.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - Token:%v  Literal Wrong. expected=%q, got=%q", i, nToken, tt.expectedLiteral, nToken.Literal)
+		}
+	}
+}
+
+func TestNextTokenOperators(t *testing.T) {
+	input := `x = 1; x += 2; x -= 3; x *= 4; x /= 5; x == -1 && a || b & c | d; a <= b >= c ** 2 < d; ...rest`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIFIER, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "4"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "x"},
+		{token.EQ, "=="},
+		{token.MINUS, "-"},
+		{token.INT, "1"},
+		{token.AND, "&&"},
+		{token.IDENTIFIER, "a"},
+		{token.OR, "||"},
+		{token.IDENTIFIER, "b"},
+		{token.AMPERSAND, "&"},
+		{token.IDENTIFIER, "c"},
+		{token.PIPE, "|"},
+		{token.IDENTIFIER, "d"},
+		{token.SEMICOLON, ";"},
+		{token.IDENTIFIER, "a"},
+		{token.LT_EQ, "<="},
+		{token.IDENTIFIER, "b"},
+		{token.GT_EQ, ">="},
+		{token.IDENTIFIER, "c"},
+		{token.POWER, "**"},
+		{token.INT, "2"},
+		{token.LT, "<"},
+		{token.IDENTIFIER, "d"},
+		{token.SEMICOLON, ";"},
+		{token.ELLIPSIS, "..."},
+		{token.IDENTIFIER, "rest"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q(%q), got=%q(%q)", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenLoopKeywords(t *testing.T) {
+	input := `while for in break continue inside`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.WHILE, "while"},
+		{token.FOR, "for"},
+		{token.IN, "in"},
+		{token.BREAK, "break"},
+		{token.CONTINUE, "continue"},
+		{token.IDENTIFIER, "inside"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q(%q), got=%q(%q)", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenMatchAndPipeline(t *testing.T) {
+	input := `match (x) { [h, ...t] => h, _ if x >= 1 => 0 } matcher |> f() || a | b`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENTIFIER, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENTIFIER, "h"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENTIFIER, "t"},
+		{token.RBRACKET, "]"},
+		{token.FAT_ARROW, "=>"},
+		{token.IDENTIFIER, "h"},
+		{token.COMMA, ","},
+		{token.IDENTIFIER, "_"},
+		{token.IF, "if"},
+		{token.IDENTIFIER, "x"},
+		{token.GT_EQ, ">="},
+		{token.INT, "1"},
+		{token.FAT_ARROW, "=>"},
+		{token.INT, "0"},
+		{token.RBRACE, "}"},
+		{token.IDENTIFIER, "matcher"},
+		{token.PIPELINE, "|>"},
+		{token.IDENTIFIER, "f"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.OR, "||"},
+		{token.IDENTIFIER, "a"},
+		{token.PIPE, "|"},
+		{token.IDENTIFIER, "b"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q(%q), got=%q(%q)", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenDot(t *testing.T) {
+	input := `person.name self.greet(...args) xs[0].y`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENTIFIER, "person"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "name"},
+		{token.IDENTIFIER, "self"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "greet"},
+		{token.LPAREN, "("},
+		{token.ELLIPSIS, "..."},
+		{token.IDENTIFIER, "args"},
+		{token.RPAREN, ")"},
+		{token.IDENTIFIER, "xs"},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.DOT, "."},
+		{token.IDENTIFIER, "y"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q(%q), got=%q(%q)", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
+
+func TestNextTokenExceptionKeywords(t *testing.T) {
+	input := `try catch finally throw trying`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.TRY, "try"},
+		{token.CATCH, "catch"},
+		{token.FINALLY, "finally"},
+		{token.THROW, "throw"},
+		{token.IDENTIFIER, "trying"},
+		{token.EOF, ""},
+	}
+
+	l := NewLexer(input)
+	for i, tt := range tests {
+		tok := l.NextToken()
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - wrong token. expected=%q(%q), got=%q(%q)", i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package lexer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ganyariya/go_monkey/token"
+)
+
+/*
+文字列補間 `${ ... }` の状態
+補間の中では通常どおりトークンを読み、対応する `}` に出会ったら文字列の続きを読む
+*/
+type interpolation struct {
+	heredoc bool // `"""` で始まる文字列の中の補間か
+	depth   int  // 補間の中で開いている `{` の数（ハッシュやブロックの `}` と区別するため）
+}
+
+/*
+`"..."` や `"""..."""` の文字列を読む（エスケープシーケンスはここでデコードする）
+
+`"hello ${name}!"` のような補間を含む文字列は次のトークン列になる
+STRING_HEAD("hello ") IDENTIFIER(name) STRING_TAIL("!")
+補間が複数あれば間の文字列は STRING_MIDDLE になる。補間を含まなければ単なる STRING になる。
+
+head = true のとき ch は開始の `"` を、false のとき補間を閉じる `}` を指した状態で呼び出す。
+終了時には ch は終端の `"`（補間の開始であれば `${` の `{`）を指す。
+*/
+func (l *Lexer) readStringPart(pos token.Position, heredoc bool, head bool) token.Token {
+	if head && heredoc {
+		// ヒアドキュメントは `"""` の直後の改行を含めない
+		l.readChar()
+		l.readChar()
+		if l.peekChar() == '\n' {
+			l.readChar()
+		} else if l.peekChar() == '\r' && l.hasPrefix("\"\r\n") {
+			l.readChar()
+			l.readChar()
+		}
+	}
+
+	var out strings.Builder
+	for {
+		l.readChar()
+		switch {
+		case l.ch == 0:
+			l.appendError(pos, "unterminated string literal")
+			return l.newStringPartToken(out.String(), head, true)
+		case heredoc && l.hasPrefix(`"""`):
+			l.readChar()
+			l.readChar()
+			return l.newStringPartToken(out.String(), head, true)
+		case !heredoc && l.ch == '"':
+			return l.newStringPartToken(out.String(), head, true)
+		case l.ch == '$' && l.peekChar() == '{':
+			l.readChar()
+			l.interpolations = append(l.interpolations, interpolation{heredoc: heredoc})
+			return l.newStringPartToken(out.String(), head, false)
+		case l.ch == '\\':
+			l.readEscape(&out)
+		default:
+			out.WriteRune(l.ch)
+		}
+	}
+}
+
+func (l *Lexer) newStringPartToken(literal string, head bool, tail bool) token.Token {
+	switch {
+	case head && tail:
+		return token.Token{Type: token.STRING, Literal: literal}
+	case head:
+		return token.Token{Type: token.STRING_HEAD, Literal: literal}
+	case tail:
+		return token.Token{Type: token.STRING_TAIL, Literal: literal}
+	default:
+		return token.Token{Type: token.STRING_MIDDLE, Literal: literal}
+	}
+}
+
+/*
+ch = `\` の状態で呼び出し、エスケープシーケンスをデコードして out に書き込む
+\n \t \r \0 \\ \" \' \$ \u{XXXX} に対応する
+不正なエスケープはエラーを記録したうえでそのまま書き込む
+*/
+func (l *Lexer) readEscape(out *strings.Builder) {
+	pos := l.currentPosition()
+	l.readChar()
+	switch l.ch {
+	case 'n':
+		out.WriteRune('\n')
+	case 't':
+		out.WriteRune('\t')
+	case 'r':
+		out.WriteRune('\r')
+	case '0':
+		out.WriteRune(0)
+	case '\\', '"', '\'', '$':
+		out.WriteRune(l.ch)
+	case 'u':
+		if r, ok := l.readUnicodeEscape(); ok {
+			out.WriteRune(r)
+			return
+		}
+		l.appendError(pos, `invalid unicode escape sequence (expected \u{XXXX})`)
+	case 0:
+		// 終端のエラーは呼び出し元で報告する（次の readChar でも 0 のまま）
+	default:
+		l.appendError(pos, "unknown escape sequence: \\"+string(l.ch))
+		out.WriteRune('\\')
+		out.WriteRune(l.ch)
+	}
+}
+
+// ch = `u` の状態で呼び出し `{XXXX}` を読む。成功したら ch は `}` を指す
+func (l *Lexer) readUnicodeEscape() (rune, bool) {
+	if l.peekChar() != '{' {
+		return 0, false
+	}
+	l.readChar()
+	start := l.readPosition
+	for isHexDigit(l.peekChar()) {
+		l.readChar()
+	}
+	hex := l.input[start:l.readPosition]
+	if l.peekChar() != '}' || hex == "" {
+		return 0, false
+	}
+	l.readChar()
+	r, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || r > 0x10FFFF {
+		return 0, false
+	}
+	return rune(r), true
+}
+
+/*
+バッククォートで囲まれた生文字列を読む
+エスケープも補間も行わず、改行を含めてそのまま値になる
+*/
+func (l *Lexer) readRawString(pos token.Position) string {
+	p := l.position + 1
+	for {
+		l.readChar()
+		if l.ch == '`' {
+			break
+		}
+		if l.ch == 0 {
+			l.appendError(pos, "unterminated raw string literal")
+			break
+		}
+	}
+	return l.input[p:l.position]
+}
+
+func isHexDigit(ch rune) bool {
+	return '0' <= ch && ch <= '9' || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
@@ -8,7 +8,15 @@ import (
 	"github.com/ganyariya/go_monkey/repl"
 )
 
+/*
+go run ./main.go               REPL を起動する
+go run ./main.go script.monkey スクリプトを実行する（エラーがあれば終了コード 1）
+*/
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runScript(os.Args[1]))
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -17,3 +25,12 @@ func main() {
 	fmt.Printf("Hello %s! This is the Monkey Programming Language!\n", user.Username)
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+func runScript(filename string) int {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return repl.RunScript(filename, string(src), os.Stderr)
+}
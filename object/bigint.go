@@ -0,0 +1,36 @@
+package object
+
+import (
+	"hash/fnv"
+	"math/big"
+)
+
+/*
+int64 に収まらない整数
+整数演算がオーバーフローしたときに Integer から昇格する
+演算結果が int64 に収まる場合は常に Integer に戻すため、同じ値の Integer と BigInt が共存することはない
+*/
+type BigInt struct {
+	Value *big.Int
+}
+
+func (b *BigInt) Type() ObjectType { return BIGINT_OBJ }
+func (b *BigInt) Inspect() string  { return b.Value.String() }
+func (b *BigInt) AsBool() bool     { return b.Value.Sign() != 0 }
+func (b *BigInt) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write(b.Value.Bytes())
+	value := h.Sum64()
+	if b.Value.Sign() < 0 {
+		value = ^value
+	}
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+// int64 に収まれば Integer、そうでなければ BigInt を返す
+func NewInteger(value *big.Int) Object {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+	return &BigInt{Value: value}
+}
@@ -8,7 +8,9 @@ package object
 type BuiltinFunction func(args ...Object) Object
 
 type Builtin struct {
-	Fn BuiltinFunction
+	Fn     BuiltinFunction
+	Params []string // 仮引数名（arity() / params() で使う）
+	Rest   string   // 可変長引数の名前（可変長でなければ空）
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
@@ -0,0 +1,41 @@
+package object
+
+/*
+呼び出し可能なオブジェクト（Function / Builtin / Macro）の共通インターフェース
+arity() や params() などの組み込み関数は種類を区別せずにこのインターフェースを使う
+*/
+type Callable interface {
+	Object
+	Arity() int               // 可変長引数（...rest）を除いた仮引数の数
+	ParameterNames() []string // 仮引数名（可変長引数は `...rest` の形式で末尾に並ぶ）
+}
+
+func (f *Function) Arity() int { return len(f.Parameters) }
+func (f *Function) ParameterNames() []string {
+	names := []string{}
+	for _, p := range f.Parameters {
+		names = append(names, p.Value)
+	}
+	if f.Rest != nil {
+		names = append(names, "..."+f.Rest.Value)
+	}
+	return names
+}
+
+func (b *Builtin) Arity() int { return len(b.Params) }
+func (b *Builtin) ParameterNames() []string {
+	names := append([]string{}, b.Params...)
+	if b.Rest != "" {
+		names = append(names, "..."+b.Rest)
+	}
+	return names
+}
+
+func (m *Macro) Arity() int { return len(m.Parameters) }
+func (m *Macro) ParameterNames() []string {
+	names := []string{}
+	for _, p := range m.Parameters {
+		names = append(names, p.Value)
+	}
+	return names
+}
@@ -8,6 +8,10 @@ type Environment struct {
 	store map[string]Object
 	// 親・外側の Environment
 	outer *Environment
+	// 独自の演算子（最も外側の Environment に登録する。nil なら外側をたどる）
+	operators *Operators
+	// 関数呼び出しの深さ（無限再帰で Go のスタックを使い果たす前にエラーにするため）
+	depth int
 }
 
 func NewEnvironment() *Environment {
@@ -16,9 +20,37 @@ func NewEnvironment() *Environment {
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	env.depth = outer.depth
 	return env
 }
 
+// 関数を呼び出すときの環境。外側は関数の定義時の環境で、深さは呼び出し元より 1 深くなる
+func NewCallEnvironment(outer *Environment, depth int) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	env.depth = depth
+	return env
+}
+
+func (e *Environment) Depth() int { return e.depth }
+
+// この Environment から見える独自の演算子の表（登録されていなければ nil）
+func (e *Environment) Operators() *Operators {
+	for env := e; env != nil; env = env.outer {
+		if env.operators != nil {
+			return env.operators
+		}
+	}
+	return nil
+}
+
+// この Environment に演算子の表を用意して返す（既にあればそれを返す）
+func (e *Environment) OwnOperators() *Operators {
+	if e.operators == nil {
+		e.operators = NewOperators()
+	}
+	return e.operators
+}
+
 func (e *Environment) Get(name string) (Object, bool) {
 	obj, ok := e.store[name]
 	if !ok && e.outer != nil {
@@ -31,3 +63,18 @@ func (e *Environment) Set(name string, obj Object) Object {
 	e.store[name] = obj
 	return obj
 }
+
+/*
+既存の束縛を書き換える（代入式 `x = value` で使う）
+Set と異なり、外側の Environment をたどって name を束縛している最も近い Environment を書き換える
+どこにも束縛がなければ false を返す
+*/
+func (e *Environment) Assign(name string, obj Object) (Object, bool) {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			env.store[name] = obj
+			return obj, true
+		}
+	}
+	return nil, false
+}
@@ -1,11 +1,102 @@
 package object
 
-import "fmt"
+import (
+	"bytes"
+	"fmt"
 
+	"github.com/ganyariya/go_monkey/token"
+)
+
+// エラーの種類（Error.Kind）
+const (
+	ERROR_KIND          = "Error" // 分類されていないエラー（throw で投げた文字列など）
+	TYPE_ERROR          = "TypeError"
+	NAME_ERROR          = "NameError"
+	ARGUMENT_ERROR      = "ArgumentError"
+	INDEX_ERROR         = "IndexError"
+	KEY_ERROR           = "KeyError"
+	VALUE_ERROR         = "ValueError" // 型は正しいが値が不正（変換できない文字列・負のシフト量など）
+	OVERFLOW_ERROR      = "OverflowError"
+	ZERO_DIVISION_ERROR = "ZeroDivisionError"
+	RECURSION_ERROR     = "RecursionError"
+)
+
+/*
+Pos = エラーの原因となった AST ノードの位置
+評価器がエラーを上に伝播させるときに最初に通過したノードの位置が入る
+Kind が空のときは ERROR_KIND として扱う。Value は throw で投げられた値（評価器が作ったエラーでは nil）
+Stack はエラーが発生したときに実行中だった関数呼び出し（内側の呼び出しが先頭）
+*/
 type Error struct {
 	Message string
+	Kind    string
+	Pos     token.Position
+	Value   Object
+	Stack   []StackFrame
+}
+
+/*
+関数呼び出し 1 回分の記録
+エラーが関数の本体から呼び出し元に戻るときに積まれる
+*/
+type StackFrame struct {
+	Function string         // 関数名（let で束縛した名前。無名関数は空）
+	Pos      token.Position // 呼び出し位置
+	Args     string         // 実引数の要約 `1, "a", [1, 2]`
+}
+
+func (f StackFrame) FunctionName() string {
+	if f.Function == "" {
+		return "<anonymous>"
+	}
+	return f.Function
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
-func (e *Error) Inspect() string  { return fmt.Sprintf("ERROR: %s", e.Message) }
-func (e *Error) AsBool() bool     { return true }
+func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return fmt.Sprintf("ERROR: %s: %s", e.Pos, e.Message)
+	}
+	return fmt.Sprintf("ERROR: %s", e.Message)
+}
+func (e *Error) AsBool() bool { return true }
+
+func (e *Error) KindName() string {
+	if e.Kind == "" {
+		return ERROR_KIND
+	}
+	return e.Kind
+}
+
+// 再帰が深いときはトレースバックの先頭と末尾だけを表示する
+const tracebackEdgeFrames = 10
+
+/*
+Python のトレースバックのように、外側の呼び出しから順に「呼び出し位置 in 呼び出し元: 呼び出し」を並べ、最後にエラーを表示する
+
+	Traceback (most recent call last):
+	  3:6 in <program>: outer(1, "a")
+	  2:25 in outer: inner(1, "a")
+	ERROR: 1:27: type mismatch: INTEGER + STRING
+*/
+func (e *Error) Traceback() string {
+	if len(e.Stack) == 0 {
+		return e.Inspect()
+	}
+	var out bytes.Buffer
+	out.WriteString("Traceback (most recent call last):\n")
+	caller := "<program>"
+	for i := len(e.Stack) - 1; i >= 0; i-- {
+		// 外側から tracebackEdgeFrames 個を表示したら、内側の tracebackEdgeFrames 個まで読み飛ばす
+		if skipped := i - tracebackEdgeFrames + 1; len(e.Stack)-1-i == tracebackEdgeFrames && skipped > 1 {
+			out.WriteString(fmt.Sprintf("  ... %d more calls ...\n", skipped))
+			i -= skipped
+			caller = e.Stack[i+1].FunctionName()
+		}
+		f := e.Stack[i]
+		out.WriteString(fmt.Sprintf("  %s in %s: %s(%s)\n", f.Pos, caller, f.FunctionName(), f.Args))
+		caller = f.FunctionName()
+	}
+	out.WriteString(e.Inspect())
+	return out.String()
+}
@@ -0,0 +1,48 @@
+package object
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Float struct {
+	Value float64
+}
+
+func (f *Float) Type() ObjectType { return FLOAT_OBJ }
+func (f *Float) Inspect() string  { return formatFloat(f.Value) }
+func (f *Float) AsBool() bool     { return f.Value != 0 }
+
+/*
+`1 == 1.0` が true になるのに合わせて、整数値と等しい Float は Integer と同じ HashKey を返す
+（{1: "a"}[1.0] で値が取り出せる）
+それ以外はビット表現をキーにする（NaN はすべて同じキーになる）
+*/
+func (f *Float) HashKey() HashKey {
+	v := f.Value
+	if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+		return (&Integer{Value: int64(v)}).HashKey()
+	}
+	if math.IsNaN(v) {
+		v = math.NaN()
+	}
+	return HashKey{Type: f.Type(), Value: math.Float64bits(v)}
+}
+
+/*
+1e-6 <= |v| < 1e21 の範囲は指数表記を使わずに表示する（JavaScript と同様）
+整数値であっても Float とわかるように `.0` をつける
+*/
+func formatFloat(v float64) string {
+	abs := math.Abs(v)
+	format := byte('g')
+	if abs == 0 || 1e-6 <= abs && abs < 1e21 {
+		format = 'f'
+	}
+	s := strconv.FormatFloat(v, format, -1, 64)
+	if !strings.ContainsAny(s, ".eIN") {
+		s += ".0"
+	}
+	return s
+}
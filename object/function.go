@@ -2,7 +2,6 @@ package object
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/ganyariya/go_monkey/ast"
 )
@@ -16,17 +15,17 @@ Env = 「Function が `定義された` 時点での環境」（親環境とも
 Function Object を定義するときは「Body」と「Parameters」は Expression のまま保持するのみ
 */
 type Function struct {
+	Name       string // `let` で束縛された名前（無名関数は空）
 	Parameters []*ast.IdentifierExpression
+	Defaults   map[string]ast.Expression
+	Rest       *ast.IdentifierExpression
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
 func (f *Function) Inspect() string {
-	params := []string{}
-	for _, p := range f.Parameters {
-		params = append(params, p.String())
-	}
-	return fmt.Sprintf("fn(%s) {\n%s\n}", strings.Join(params, ","), f.Body.String())
+	params := ast.FormatParameters(f.Parameters, f.Defaults, f.Rest)
+	return fmt.Sprintf("fn(%s) {\n%s\n}", params, f.Body.String())
 }
 func (f *Function) AsBool() bool { return true }
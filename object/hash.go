@@ -1,10 +1,5 @@
 package object
 
-import (
-	"fmt"
-	"strings"
-)
-
 type HashKey struct {
 	/*
 		String or Integer or Boolean
@@ -18,20 +13,75 @@ type HashPair struct {
 	Key   Object
 	Value Object
 }
+
+/*
+挿入順を保つハッシュ
+Inspect・for 文・keys/values は最初に追加された順にペアを並べる（既存のキーへの代入は順番を変えない）
+*/
 type Hash struct {
 	/*
 		HashKey は Type と ハッシュ化された Key が入る（"Hello" -> 489281121）
 		そのため HashPair にハッシュする前のオリジナルオブジェクト key: value を保存する
 	*/
-	Pairs map[HashKey]HashPair
+	pairs map[HashKey]HashPair
+	order []HashKey // 挿入順
 }
 
-func (h *Hash) Type() ObjectType { return HASH_OBJ }
-func (h *Hash) Inspect() string {
-	pairs := []string{}
-	for _, pair := range h.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key.Inspect(), pair.Value.Inspect()))
+func NewHash() *Hash {
+	return &Hash{pairs: map[HashKey]HashPair{}}
+}
+
+func (h *Hash) Get(key HashKey) (HashPair, bool) {
+	pair, ok := h.pairs[key]
+	return pair, ok
+}
+
+// 既にあるキーなら値だけを書き換え、最初に追加したキーのオブジェクトを残す（1 と 1.0 は同じキー）
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	if h.pairs == nil {
+		h.pairs = map[HashKey]HashPair{}
+	}
+	if existing, ok := h.pairs[key]; ok {
+		pair.Key = existing.Key
+	} else {
+		h.order = append(h.order, key)
 	}
-	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+	h.pairs[key] = pair
 }
-func (h *Hash) AsBool() bool { return len(h.Pairs) > 0 }
+
+func (h *Hash) Delete(key HashKey) {
+	if _, ok := h.pairs[key]; !ok {
+		return
+	}
+	delete(h.pairs, key)
+	for i, k := range h.order {
+		if k == key {
+			h.order = append(h.order[:i:i], h.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (h *Hash) Len() int { return len(h.pairs) }
+
+// 挿入順に並んだペア
+func (h *Hash) Pairs() []HashPair {
+	pairs := make([]HashPair, 0, len(h.order))
+	for _, key := range h.order {
+		pairs = append(pairs, h.pairs[key])
+	}
+	return pairs
+}
+
+// 挿入順にペアを fn に渡す（fn が false を返したら打ち切る）
+func (h *Hash) Range(fn func(HashPair) bool) {
+	for _, key := range h.order {
+		if !fn(h.pairs[key]) {
+			return
+		}
+	}
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string  { return h.inspect(map[Object]bool{}) }
+func (h *Hash) AsBool() bool     { return h.Len() > 0 }
@@ -0,0 +1,50 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+配列・ハッシュは代入で自分自身を含められるため、表示中のコンテナを覚えておく
+表示中のコンテナに再び出会ったら `[...]` / `{...}` と表示して打ち切る
+*/
+func inspectWithVisited(obj Object, visiting map[Object]bool) string {
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.inspect(visiting)
+	case *Hash:
+		return obj.inspect(visiting)
+	}
+	return obj.Inspect()
+}
+
+func (a *Array) inspect(visiting map[Object]bool) string {
+	if visiting[a] {
+		return "[...]"
+	}
+	visiting[a] = true
+	defer delete(visiting, a)
+
+	elements := []string{}
+	for _, e := range a.Elements {
+		elements = append(elements, inspectWithVisited(e, visiting))
+	}
+	return fmt.Sprintf("[%s]", strings.Join(elements, ", "))
+}
+
+func (h *Hash) inspect(visiting map[Object]bool) string {
+	if visiting[h] {
+		return "{...}"
+	}
+	visiting[h] = true
+	defer delete(visiting, h)
+
+	pairs := []string{}
+	for _, pair := range h.Pairs() {
+		key := inspectWithVisited(pair.Key, visiting)
+		value := inspectWithVisited(pair.Value, visiting)
+		pairs = append(pairs, fmt.Sprintf("%s: %s", key, value))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(pairs, ", "))
+}
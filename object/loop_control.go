@@ -0,0 +1,17 @@
+package object
+
+/*
+break / continue を表すシグナル
+ReturnValue と同様にブロック文を抜けて、それを囲むループまで伝搬する
+*/
+type Break struct{}
+
+func (b *Break) Type() ObjectType { return BREAK_OBJ }
+func (b *Break) Inspect() string  { return "break" }
+func (b *Break) AsBool() bool     { return false }
+
+type Continue struct{}
+
+func (c *Continue) Type() ObjectType { return CONTINUE_OBJ }
+func (c *Continue) Inspect() string  { return "continue" }
+func (c *Continue) AsBool() bool     { return false }
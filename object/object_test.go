@@ -1,6 +1,12 @@
 package object
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ganyariya/go_monkey/token"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello"}
@@ -14,3 +20,139 @@ func TestStringHashKey(t *testing.T) {
 		t.Fatalf("different object has same hash key ")
 	}
 }
+
+func TestStringRunes(t *testing.T) {
+	s := &String{Value: "aこんにちは"}
+
+	if s.Len() != 6 {
+		t.Fatalf("wrong length. expected=6, got=%d", s.Len())
+	}
+	if ch, ok := s.At(2); !ok || ch.Value != "ん" {
+		t.Fatalf("wrong char at 2. got=%v(%t)", ch, ok)
+	}
+	if _, ok := s.At(6); ok {
+		t.Fatalf("At(6) should be out of range")
+	}
+	chars := s.Chars()
+	if len(chars) != 6 || chars[0].Value != "a" || chars[5].Value != "は" {
+		t.Fatalf("wrong chars. got=%v", chars)
+	}
+	if sub := s.Slice(1, 4); sub.Value != "こんに" {
+		t.Fatalf("wrong slice. got=%q", sub.Value)
+	}
+}
+
+func TestFloatHashKey(t *testing.T) {
+	if (&Float{Value: 1.0}).HashKey() != (&Integer{Value: 1}).HashKey() {
+		t.Fatalf("integral float does not have same hash key as integer")
+	}
+	if (&Float{Value: -0.0}).HashKey() != (&Integer{Value: 0}).HashKey() {
+		t.Fatalf("-0.0 does not have same hash key as 0")
+	}
+	if (&Float{Value: 1.5}).HashKey() != (&Float{Value: 1.5}).HashKey() {
+		t.Fatalf("same float does not have same hash key")
+	}
+	if (&Float{Value: 1.5}).HashKey() == (&Float{Value: 2.5}).HashKey() {
+		t.Fatalf("different float has same hash key")
+	}
+}
+
+func TestFloatInspect(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{3.14, "3.14"},
+		{2, "2.0"},
+		{-0.5, "-0.5"},
+		{1e-9, "1e-09"},
+		{1e21, "1e+21"},
+		{123456789, "123456789.0"},
+	}
+	for _, tt := range tests {
+		if got := (&Float{Value: tt.value}).Inspect(); got != tt.expected {
+			t.Errorf("wrong inspect. expected=%s, got=%s", tt.expected, got)
+		}
+	}
+}
+
+func TestErrorTraceback(t *testing.T) {
+	err := &Error{Message: "type mismatch: INTEGER + STRING", Pos: token.Position{Line: 1, Column: 27}}
+	if err.Traceback() != err.Inspect() {
+		t.Fatalf("error without stack should be same as Inspect. got=%q", err.Traceback())
+	}
+	err.Stack = []StackFrame{
+		{Function: "inner", Pos: token.Position{Line: 2, Column: 25}, Args: `1, "a"`},
+		{Function: "", Pos: token.Position{Line: 3, Column: 6}, Args: `1, "a"`},
+	}
+	expected := `Traceback (most recent call last):
+  3:6 in <program>: <anonymous>(1, "a")
+  2:25 in <anonymous>: inner(1, "a")
+ERROR: 1:27: type mismatch: INTEGER + STRING`
+	if err.Traceback() != expected {
+		t.Fatalf("wrong traceback. expected=%q, got=%q", expected, err.Traceback())
+	}
+
+	// 深い再帰は先頭と末尾の呼び出しだけを表示する
+	err.Stack = nil
+	for i := 0; i < 25; i++ {
+		err.Stack = append(err.Stack, StackFrame{Function: "f", Pos: token.Position{Line: 1, Column: 1}, Args: fmt.Sprint(i)})
+	}
+	lines := strings.Split(err.Traceback(), "\n")
+	if len(lines) != 1+10+1+10+1 {
+		t.Fatalf("wrong number of lines. got=%d\n%s", len(lines), err.Traceback())
+	}
+	if lines[1] != "  1:1 in <program>: f(24)" || lines[11] != "  ... 5 more calls ..." || lines[21] != "  1:1 in f: f(0)" {
+		t.Fatalf("wrong traceback. got=\n%s", err.Traceback())
+	}
+}
+
+func TestInspectSelfReferentialContainers(t *testing.T) {
+	arr := &Array{Elements: []Object{&Integer{Value: 1}}}
+	arr.Elements = append(arr.Elements, arr)
+	if arr.Inspect() != "[1, [...]]" {
+		t.Fatalf("wrong inspect. got=%q", arr.Inspect())
+	}
+
+	hash := NewHash()
+	key := &String{Value: "self"}
+	hash.Set(key.HashKey(), HashPair{Key: key, Value: hash})
+	inner := &String{Value: "arr"}
+	hash.Set(inner.HashKey(), HashPair{Key: inner, Value: &Array{Elements: []Object{hash, arr}}})
+	if hash.Inspect() != "{self: {...}, arr: [{...}, [1, [...]]]}" {
+		t.Fatalf("wrong inspect. got=%q", hash.Inspect())
+	}
+
+	// 循環していない共有は省略しない
+	shared := &Array{Elements: []Object{&Integer{Value: 2}}}
+	pair := &Array{Elements: []Object{shared, shared}}
+	if pair.Inspect() != "[[2], [2]]" {
+		t.Fatalf("wrong inspect. got=%q", pair.Inspect())
+	}
+}
+
+func TestStringRuneIndexing(t *testing.T) {
+	s := &String{Value: "aあ🐒b"}
+	if s.Len() != 4 {
+		t.Fatalf("wrong len. got=%d", s.Len())
+	}
+	expected := []string{"a", "あ", "🐒", "b"}
+	for i, want := range expected {
+		ch, ok := s.At(i)
+		if !ok || ch.Value != want {
+			t.Fatalf("At(%d) wrong. expected=%q, got=%v", i, want, ch)
+		}
+	}
+	if _, ok := s.At(4); ok {
+		t.Fatalf("At(4) should be out of range")
+	}
+	if _, ok := s.At(-1); ok {
+		t.Fatalf("At(-1) should be out of range")
+	}
+	if s.Len() != 4 || s.Slice(1, 3).Value != "あ🐒" {
+		t.Fatalf("wrong slice. got=%q", s.Slice(1, 3).Value)
+	}
+	if empty := (&String{}); empty.Len() != 0 {
+		t.Fatalf("empty string should have len 0")
+	}
+}
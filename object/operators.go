@@ -0,0 +1,32 @@
+package object
+
+/*
+埋め込み先が Go で定義する独自の演算子（evaluator.RegisterInfixOperator などで登録する）
+Environment ごとに持つので、ある評価で登録した演算子は他の評価に影響しない
+*/
+type (
+	PrefixOperatorFn func(right Object) Object
+	InfixOperatorFn  func(left, right Object) Object
+)
+
+type Operators struct {
+	prefix map[string]PrefixOperatorFn
+	infix  map[string]InfixOperatorFn
+}
+
+func NewOperators() *Operators {
+	return &Operators{prefix: map[string]PrefixOperatorFn{}, infix: map[string]InfixOperatorFn{}}
+}
+
+func (o *Operators) RegisterPrefix(operator string, fn PrefixOperatorFn) { o.prefix[operator] = fn }
+func (o *Operators) RegisterInfix(operator string, fn InfixOperatorFn)   { o.infix[operator] = fn }
+
+func (o *Operators) Prefix(operator string) (PrefixOperatorFn, bool) {
+	fn, ok := o.prefix[operator]
+	return fn, ok
+}
+
+func (o *Operators) Infix(operator string) (InfixOperatorFn, bool) {
+	fn, ok := o.infix[operator]
+	return fn, ok
+}
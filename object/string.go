@@ -1,9 +1,18 @@
 package object
 
-import "hash/fnv"
+import (
+	"hash/fnv"
+	"unicode/utf8"
+)
 
+/*
+Value は UTF-8 の文字列
+長さ・添字・反復はすべてバイトではなくルーン（文字）単位で扱う
+String は作成後に Value を書き換えない（ルーン列をキャッシュするため）
+*/
 type String struct {
 	Value string
+	runes []rune // Value をルーンに分解したもの（最初に必要になったときに作る）
 }
 
 func (s *String) Type() ObjectType { return STRING_OBJ }
@@ -16,3 +25,42 @@ func (s *String) HashKey() HashKey {
 	h.Write([]byte(s.Value))
 	return HashKey{Type: s.Type(), Value: h.Sum64()}
 }
+
+// ルーン単位の添字を O(1) で引けるよう、ルーン列は一度だけ作って使い回す
+func (s *String) runeSlice() []rune {
+	if s.runes == nil {
+		s.runes = []rune(s.Value)
+	}
+	return s.runes
+}
+
+// ルーン数を返す
+func (s *String) Len() int {
+	if s.runes != nil {
+		return len(s.runes)
+	}
+	return utf8.RuneCountInString(s.Value)
+}
+
+// idx 番目（ルーン単位）の文字を 1 文字の String として返す
+func (s *String) At(idx int) (*String, bool) {
+	runes := s.runeSlice()
+	if idx < 0 || idx >= len(runes) {
+		return nil, false
+	}
+	return &String{Value: string(runes[idx])}, true
+}
+
+// [start, end) の範囲（ルーン単位）の部分文字列を返す（0 <= start <= end <= Len() であること）
+func (s *String) Slice(start, end int) *String {
+	return &String{Value: string(s.runeSlice()[start:end])}
+}
+
+// 1 文字ずつの String に分解する（反復用）
+func (s *String) Chars() []*String {
+	chars := make([]*String, 0, len(s.Value))
+	for _, r := range s.Value {
+		chars = append(chars, &String{Value: string(r)})
+	}
+	return chars
+}
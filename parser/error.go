@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ganyariya/go_monkey/token"
+)
+
+type Severity int
+
+const (
+	SeverityError   Severity = iota // 構文エラー（プログラムを評価できない）
+	SeverityWarning                 // 警告（評価はできる）
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityError:
+		return "error"
+	case SeverityWarning:
+		return "warning"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+/*
+構文解析の診断情報
+エディタなどのツールが位置と「期待していたトークン」「実際に見つかったトークン」を扱えるように構造化している
+*/
+type ParseError struct {
+	Pos      token.Position
+	Message  string
+	Expected []token.TokenType // 期待していたトークン（特定できない場合は空）
+	Found    token.Token       // 実際に見つかったトークン
+	Severity Severity
+}
+
+// `file:line:col: message` の形式（警告には `warning: ` がつく）
+func (e *ParseError) Error() string {
+	if e.Severity == SeverityWarning {
+		return fmt.Sprintf("%s: warning: %s", e.Pos, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+/*
+字句エラーと構文エラー・警告をソースコード上の位置順に返す
+*/
+func (p *Parser) Diagnostics() []*ParseError {
+	diagnostics := []*ParseError{}
+	for _, e := range p.l.Errors() {
+		diagnostics = append(diagnostics, &ParseError{
+			Pos:      e.Pos,
+			Message:  e.Message,
+			Found:    token.Token{Type: token.ILLEGAL, Pos: e.Pos},
+			Severity: SeverityError,
+		})
+	}
+	diagnostics = append(diagnostics, p.errors...)
+	sort.SliceStable(diagnostics, func(i, j int) bool {
+		return diagnostics[i].Pos.Offset < diagnostics[j].Pos.Offset
+	})
+	return diagnostics
+}
+
+// 警告を除いたエラーメッセージ（`file:line:col: message`）を返す
+func (p *Parser) Errors() []string {
+	errors := []string{}
+	for _, d := range p.Diagnostics() {
+		if d.Severity == SeverityError {
+			errors = append(errors, d.Error())
+		}
+	}
+	return errors
+}
+
+/*
+エラーを記録してパニックモードに入る
+パニックモードの間（= 次の同期点に達するまで）のエラーは連鎖的に発生したものとみなして記録しない
+*/
+func (p *Parser) addError(err *ParseError) {
+	if p.panicking {
+		return
+	}
+	p.panicking = true
+	p.errors = append(p.errors, err)
+}
+
+func (p *Parser) addWarning(pos token.Position, found token.Token, msg string) {
+	p.errors = append(p.errors, &ParseError{Pos: pos, Message: msg, Found: found, Severity: SeverityWarning})
+}
+
+/*
+エラーが起きた文の残りを読み飛ばして同期点まで進める
+同期点
+- 文の区切りである `;`
+- 次の文の開始である `let` `return`
+- 文を囲むブロックを閉じる `}`（文の中で開いた `{` `}` は読み飛ばす）
+終了時には curToken が同期点の直前（もしくは `;`）を指す
+すでに文を囲むブロックの `}` まで読み進めていた場合はそこで止まる
+*/
+func (p *Parser) synchronize(depth int) {
+	for !p.peekTokenIs(token.EOF) && p.depth >= depth {
+		// curToken が `{` のとき peekToken はそのブロックの中にある
+		if p.depth == depth && !p.curTokenIs(token.LBRACE) {
+			if p.curTokenIs(token.SEMICOLON) {
+				break
+			}
+			if p.peekTokenIs(token.LET) || p.peekTokenIs(token.RETURN) || p.peekTokenIs(token.RBRACE) {
+				break
+			}
+		}
+		p.nextToken()
+	}
+	p.panicking = false
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/token"
+)
+
+/*
+独自の演算子や構文を追加するための公開 API
+
+	p := parser.NewParser(lexer.NewLexer(input))
+	p.RegisterInfixOperator("=~", "MATCH", parser.EQUALS)
+	p.RegisterInfixOperator("..", "RANGE", parser.SUM-1)
+	program := p.ParseProgram()
+
+演算子として登録したトークンは ast.PrefixExpression / ast.InfixExpression（Operator = literal）になる。
+評価器での意味は evaluator.RegisterPrefixOperator / evaluator.RegisterInfixOperator で与える。
+登録は ParseProgram を呼び出す前に行う。
+*/
+
+// 構文解析関数（curToken が関連付けられたトークンを指した状態で呼び出され、式の最後のトークンを指した状態で終了する）
+type (
+	PrefixParseFn = func() ast.Expression
+	InfixParseFn  = func(ast.Expression) ast.Expression
+)
+
+// literal を tokenType のトークンとして字句解析する
+func (p *Parser) RegisterToken(literal string, tokenType token.TokenType) {
+	p.l.RegisterToken(literal, tokenType)
+}
+
+// tokenType から始まる式の構文解析関数を登録する（既存の関数は上書きされる）
+func (p *Parser) RegisterPrefix(tokenType token.TokenType, fn PrefixParseFn) {
+	p.registerPrefixFn(tokenType, fn)
+}
+
+// tokenType を中置する式の構文解析関数を優先順位とともに登録する（既存の関数は上書きされる）
+func (p *Parser) RegisterInfix(tokenType token.TokenType, precedence int, fn InfixParseFn) {
+	p.registerInfixFn(tokenType, fn)
+	p.precedences[tokenType] = precedence
+}
+
+// literal を前置演算子として登録する（`-x` と同じく ast.PrefixExpression になる）
+func (p *Parser) RegisterPrefixOperator(literal string, tokenType token.TokenType) {
+	p.RegisterToken(literal, tokenType)
+	p.RegisterPrefix(tokenType, p.parsePrefixExpression)
+}
+
+// literal を左結合の中置演算子として登録する（`a + b` と同じく ast.InfixExpression になる）
+func (p *Parser) RegisterInfixOperator(literal string, tokenType token.TokenType, precedence int) {
+	p.RegisterToken(literal, tokenType)
+	p.RegisterInfix(tokenType, precedence, p.parseInfixExpression)
+}
+
+/*
+独自の構文解析関数から使う操作
+*/
+
+func (p *Parser) CurToken() token.Token  { return p.curToken }
+func (p *Parser) PeekToken() token.Token { return p.peekToken }
+func (p *Parser) NextToken()             { p.nextToken() }
+
+// 次のトークンが t であれば読み進めて true を返す。そうでなければエラーを記録して false を返す
+func (p *Parser) ExpectPeek(t token.TokenType) bool { return p.expectPeek(t) }
+
+// curToken から始まる式を precedence の右結合力で解析する
+func (p *Parser) ParseExpression(precedence int) ast.Expression {
+	return p.parseExpression(precedence)
+}
+
+// curToken の優先順位（中置演算子の右辺を解析するときに使う）
+func (p *Parser) CurPrecedence() int { return p.curPrecedence() }
+
+// curToken の位置に構文エラーを記録する
+func (p *Parser) ReportError(msg string) { p.curTokenError(msg) }
@@ -10,30 +10,62 @@ import (
 )
 
 // 順序が重要（PRODUCT は EQUALS よりも高い優先順位）
+// ビット演算子は Python と同様に比較演算子よりも強く結合する（`x & MASK == 0` は `(x & MASK) == 0`）
+// 独自の演算子を既存の優先順位の間に登録できるように 10 刻みにしている（`SUM - 1` など）
 const (
-	_ int = iota
+	_ int = iota * 10
 	LOWEST
+	ASSIGN      // = or += (右結合)
+	ARROW       // x => x * 2 (ラムダ式。本体は LOWEST で読むので右側の式をすべて含む)
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
 	EQUALS      // ==
 	LESSGREATER // < or >
+	PIPELINE    // |> (`xs |> len() == 3` は `len(xs) == 3`、`a + b |> f()` は `f(a + b)`)
+	BIT_OR      // |
+	BIT_XOR     // ^
+	BIT_AND     // &
+	SHIFT       // << or >>
 	SUM         // +
-	PRODUCT     // *
-	PREFIX      // - or !
+	PRODUCT     // * or / or %
+	PREFIX      // - or ! or ~
+	POWER       // ** (右結合。`-2 ** 2` は `-(2 ** 2)`)
 	CALL        // func()
 	INDEX       // array[index]
 )
 
-// 中置演算子の優先順位
+// 中置演算子の優先順位（Parser ごとにコピーして使う）
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.FAT_ARROW:       ARROW,
+	token.OR:              LOGICAL_OR,
+	token.AND:             LOGICAL_AND,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.IS:              EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.LT_EQ:           LESSGREATER,
+	token.GT_EQ:           LESSGREATER,
+	token.PIPELINE:        PIPELINE,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         PRODUCT,
+	token.POWER:           POWER,
+	token.PIPE:            BIT_OR,
+	token.CARET:           BIT_XOR,
+	token.AMPERSAND:       BIT_AND,
+	token.LSHIFT:          SHIFT,
+	token.RSHIFT:          SHIFT,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
+	token.DOT:             INDEX,
 }
 
 /*
@@ -50,102 +82,211 @@ type (
 
 type Parser struct {
 	l      *lexer.Lexer
-	errors []string
+	errors []*ParseError
+
+	// エラー回復のための状態
+	panicking bool // エラーが起きてから同期点に達するまで true（連鎖的なエラーを記録しない）
+	depth     int  // curToken より前にある閉じられていない `{` の数
+
+	loops int // 解析中の文を囲むループの数（関数の中では 0 に戻る）
 
 	curToken  token.Token // 今見ているトークン
 	peekToken token.Token // 先読みトークン
 
+	// まだどの文にも付与されていないコメント（次に構文解析する文の LeadingComments になる）
+	comments []*ast.Comment
+
 	// トークンに対応する構文解析関数 map
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+	precedences    map[token.TokenType]int
+
+	started bool // 最初のトークンを読み込んだか
 }
 
 func NewParser(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l, errors: []string{}}
+	p := &Parser{l: l, errors: []*ParseError{}}
 
 	// 式を構文解析する prefixParseExpression をトークンタイプごとに登録する
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefixFn(token.IDENTIFIER, p.parseIdentifierExpression)
 	p.registerPrefixFn(token.INT, p.parseIntegerLiteralExpression)
+	p.registerPrefixFn(token.FLOAT, p.parseFloatLiteralExpression)
 	p.registerPrefixFn(token.BANG, p.parsePrefixExpression)
 	p.registerPrefixFn(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefixFn(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefixFn(token.TRUE, p.parseBooleanExpression)
 	p.registerPrefixFn(token.FALSE, p.parseBooleanExpression)
+	p.registerPrefixFn(token.NULL, p.parseNullLiteralExpression)
 	p.registerPrefixFn(token.LPAREN, p.parseGroupedExpression)
 	p.registerPrefixFn(token.IF, p.parseIfExpression)
 	p.registerPrefixFn(token.FUNCTION, p.parseFunctionExpression)
 	p.registerPrefixFn(token.STRING, p.parseStringLiteralExpression)
+	p.registerPrefixFn(token.STRING_HEAD, p.parseInterpolatedStringExpression)
 	p.registerPrefixFn(token.LBRACKET, p.parseArrayLiteralExpression)
 	p.registerPrefixFn(token.LBRACE, p.parseHashLiteralExpression)
 	p.registerPrefixFn(token.MACRO, p.parseMacroExpression)
+	p.registerPrefixFn(token.MATCH, p.parseMatchExpression)
+	p.registerPrefixFn(token.TRY, p.parseTryExpression)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfixFn(token.PLUS, p.parseInfixExpression)
 	p.registerInfixFn(token.MINUS, p.parseInfixExpression)
 	p.registerInfixFn(token.SLASH, p.parseInfixExpression)
 	p.registerInfixFn(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfixFn(token.PERCENT, p.parseInfixExpression)
+	p.registerInfixFn(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfixFn(token.PIPE, p.parseInfixExpression)
+	p.registerInfixFn(token.CARET, p.parseInfixExpression)
+	p.registerInfixFn(token.LSHIFT, p.parseInfixExpression)
+	p.registerInfixFn(token.RSHIFT, p.parseInfixExpression)
+	p.registerInfixFn(token.AND, p.parseInfixExpression)
+	p.registerInfixFn(token.OR, p.parseInfixExpression)
 	p.registerInfixFn(token.EQ, p.parseInfixExpression)
 	p.registerInfixFn(token.NOT_EQ, p.parseInfixExpression)
+	p.registerInfixFn(token.IS, p.parseInfixExpression)
 	p.registerInfixFn(token.LT, p.parseInfixExpression)
 	p.registerInfixFn(token.GT, p.parseInfixExpression)
+	p.registerInfixFn(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfixFn(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfixFn(token.POWER, p.parseRightAssociativeInfixExpression)
+	p.registerInfixFn(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfixFn(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfixFn(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfixFn(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfixFn(token.SLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfixFn(token.PIPELINE, p.parsePipelineExpression)
+	p.registerInfixFn(token.FAT_ARROW, p.parseArrowFunctionExpression)
 	p.registerInfixFn(token.LPAREN, p.parseCallExpression)
 	p.registerInfixFn(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfixFn(token.DOT, p.parseMemberExpression)
+
+	p.precedences = make(map[token.TokenType]int, len(precedences))
+	for t, precedence := range precedences {
+		p.precedences[t] = precedence
+	}
+	return p
+}
 
+// curToken と peekToken を読み込む
+// RegisterToken で追加したトークンが使われるように、構文解析を始めるまで読み込みを遅らせる
+func (p *Parser) start() {
+	if p.started {
+		return
+	}
+	p.started = true
 	p.nextToken()
 	p.nextToken()
-	return p
 }
 
+// コメントは構文解析の対象にせず p.comments に退避する
 func (p *Parser) nextToken() {
+	if p.curTokenIs(token.LBRACE) {
+		p.depth++
+	}
 	p.curToken = p.peekToken
+	if p.curTokenIs(token.RBRACE) {
+		p.depth--
+	}
 	p.peekToken = p.l.NextToken()
+	for p.peekTokenIs(token.COMMENT) {
+		trailing := p.curToken.Pos.IsValid() && p.curToken.Pos.Line == p.peekToken.Pos.Line
+		p.comments = append(p.comments, &ast.Comment{Token: p.peekToken, Trailing: trailing})
+		p.peekToken = p.l.NextToken()
+	}
+}
+
+// curToken より前にあるコメントを取り出す
+func (p *Parser) takeLeadingComments() []*ast.Comment {
+	i := 0
+	for i < len(p.comments) && p.comments[i].Pos().Offset < p.curToken.Pos.Offset {
+		i++
+	}
+	if i == 0 {
+		return nil
+	}
+	comments := p.comments[:i]
+	p.comments = p.comments[i:]
+	return comments
 }
 
 // Parser は与えられたソースコードをトークンごとに読み込んでパースする
 // パースした結果の Statement 列を ast.Program として返す
 func (p *Parser) ParseProgram() *ast.Program {
+	p.start()
 	program := &ast.Program{}
 	program.Statements = []ast.Statement{}
 
 	for !p.curTokenIs(token.EOF) {
-		stmt := p.parseStatement()
-		if stmt != nil {
-			program.Statements = append(program.Statements, stmt)
-		}
+		program.Statements = append(program.Statements, p.parseStatement())
 		p.nextToken()
 	}
+	program.Comments = p.comments
 
 	return program
 }
 
-// 様々な Statement をパースする
+/*
+様々な Statement をパースする（直前のコメントを文に付与する）
+文の中でエラーが起きた場合は同期点まで読み飛ばし、文として解析できなければ BadStatement を返す
+*/
 func (p *Parser) parseStatement() ast.Statement {
+	comments := p.takeLeadingComments()
+	start, depth := p.curToken, p.depth
+	var stmt ast.Statement
 	switch p.curToken.Type {
 	case token.LET:
-		return p.parseLetStatement()
+		stmt = p.parseLetStatement()
 	case token.RETURN:
-		return p.parseReturnStatement()
+		stmt = p.parseReturnStatement()
+	case token.WHILE:
+		stmt = p.parseWhileStatement()
+	case token.FOR:
+		stmt = p.parseForStatement()
+	case token.BREAK, token.CONTINUE:
+		stmt = p.parseLoopControlStatement()
+	case token.THROW:
+		stmt = p.parseThrowStatement()
 	default:
 		// let return 以外は Expression のみからなる Statement
-		return p.parseExpressionStatement()
+		stmt = p.parseExpressionStatement()
+	}
+	if p.panicking {
+		p.synchronize(depth)
 	}
+	if stmt == nil {
+		stmt = &ast.BadStatement{Token: start, To: p.curToken}
+	}
+	stmt.SetLeadingComments(comments)
+	return stmt
 }
 
 // Let Statement をパースする
 func (p *Parser) parseLetStatement() ast.Statement {
 	stmt := &ast.LetStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.IDENTIFIER) {
-		return nil
+	// let [a, b] = xs; let {"k": v} = h; （分割代入）
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		if stmt.Pattern = p.parsePattern(); stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		stmt.Name = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
 	}
-
-	stmt.Name = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
 
 	p.nextToken()
 	stmt.Value = p.parseExpression(LOWEST)
+	// 関数に束縛された名前をもたせる（エラーメッセージやスタックトレースに使う）
+	if fe, ok := stmt.Value.(*ast.FunctionExpression); ok && stmt.Name != nil {
+		fe.Name = stmt.Name.Value
+	}
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.nextToken()
 	}
@@ -163,6 +304,81 @@ func (p *Parser) parseReturnStatement() ast.Statement {
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() ast.Statement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return stmt
+}
+
+func (p *Parser) parseWhileStatement() ast.Statement {
+	stmt := &ast.WhileStatement{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseLoopBody()
+	return stmt
+}
+
+// for (x in iterable) { ... }
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	stmt.Variable = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	stmt.Body = p.parseLoopBody()
+	return stmt
+}
+
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	p.loops++
+	defer func() { p.loops-- }()
+	return p.parseBlockStatement()
+}
+
+// break; continue; （ループの外で使うとエラー）
+func (p *Parser) parseLoopControlStatement() ast.Statement {
+	tok := p.curToken
+	if p.loops == 0 {
+		p.curTokenError(fmt.Sprintf("%s outside loop", tok.Literal))
+		return nil
+	}
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+	if tok.Type == token.BREAK {
+		return &ast.BreakStatement{Token: tok}
+	}
+	return &ast.ContinueStatement{Token: tok}
+}
+
 // セミコロンが来るまで「一つの大きな式」として ExpressionStatement をパースする
 func (p *Parser) parseExpressionStatement() ast.Statement {
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
@@ -178,15 +394,25 @@ func (p *Parser) parseExpressionStatement() ast.Statement {
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken} // Token = token.LBRACE
 	block.Statements = []ast.Statement{}
+	depth := p.depth
 	p.nextToken()
 
 	for !p.curTokenIs(token.RBRACE) && !p.curTokenIs(token.EOF) {
-		stmt := p.parseStatement()
-		if stmt != nil {
-			block.Statements = append(block.Statements, stmt)
+		block.Statements = append(block.Statements, p.parseStatement())
+		// エラー回復中にブロックを閉じる `}` まで読み進めていたら終了する
+		if p.depth <= depth {
+			break
 		}
 		p.nextToken()
 	}
+	if p.curTokenIs(token.EOF) {
+		p.addError(&ParseError{
+			Pos:      p.curToken.Pos,
+			Message:  "expected next token to be RBRACE, got EOF instead.",
+			Expected: []token.TokenType{token.RBRACE},
+			Found:    p.curToken,
+		})
+	}
 	return block
 }
 
@@ -212,12 +438,14 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefixFn := p.prefixParseFns[p.curToken.Type]
 	if prefixFn == nil {
 		p.noPrefixParseFnError(p.curToken.Type)
-		return nil
+		return &ast.BadExpression{Token: p.curToken}
 	}
-	leftExp := prefixFn()
+	start := p.curToken
+	leftExp := p.badIfNil(prefixFn(), start)
 
 	// セミコロンが来る もしくは 優先順位が上がらなくなったら
-	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() {
+	// エラーが起きたら（パニックモード）それ以上式を伸ばさない
+	for !p.peekTokenIs(token.SEMICOLON) && precedence < p.peekPrecedence() && !p.panicking {
 		// 次の中間演算子の優先順位の方が高いなら次の中置演算子に紐付いた関数でパースする
 		infixFn := p.infixParseFns[p.peekToken.Type]
 		if infixFn == nil {
@@ -225,12 +453,21 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 		}
 		p.nextToken()
 		// 「これまで見ていた"中置演算子の左側にある"式」を「これから見る中間演算子式のLeft」として埋め込む
-		leftExp = infixFn(leftExp)
+		operator := p.curToken
+		leftExp = p.badIfNil(infixFn(leftExp), operator)
 	}
 
 	return leftExp
 }
 
+// 構文解析関数が失敗して nil を返したときに BadExpression に置き換える
+func (p *Parser) badIfNil(exp ast.Expression, tok token.Token) ast.Expression {
+	if exp == nil {
+		return &ast.BadExpression{Token: tok}
+	}
+	return exp
+}
+
 // ----------------------------------------------------------------------------
 // ----------------------------------------------------------------------------
 
@@ -246,22 +483,71 @@ func (p *Parser) parseIntegerLiteralExpression() ast.Expression {
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 	if err != nil {
 		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.curTokenError(msg)
 		return nil
 	}
+	if isLegacyOctal(p.curToken.Literal) {
+		msg := fmt.Sprintf("%q is parsed as an octal literal; use the 0o prefix to make it explicit", p.curToken.Literal)
+		p.addWarning(p.curToken.Pos, p.curToken, msg)
+	}
 	ile.Value = value
 	return ile
 }
 
+// `017` のような 0 始まりの整数（strconv によって 8 進数として解釈される）
+func isLegacyOctal(literal string) bool {
+	return len(literal) > 1 && literal[0] == '0' && '0' <= literal[1] && literal[1] <= '9'
+}
+
+func (p *Parser) parseFloatLiteralExpression() ast.Expression {
+	fle := &ast.FloatLiteralExpression{Token: p.curToken}
+	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
+	if err != nil {
+		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
+		p.curTokenError(msg)
+		return nil
+	}
+	fle.Value = value
+	return fle
+}
+
 // token.TRUE か FALSE がトークンとして与えられるので「token.TRUE」かを判定することで true/false 式にする
 func (p *Parser) parseBooleanExpression() ast.Expression {
 	return &ast.BooleanExpression{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
 
+func (p *Parser) parseNullLiteralExpression() ast.Expression {
+	return &ast.NullLiteralExpression{Token: p.curToken}
+}
+
 func (p *Parser) parseStringLiteralExpression() ast.Expression {
 	return &ast.StringLiteralExpression{Token: p.curToken, Value: p.curToken.Literal}
 }
 
+/*
+STRING_HEAD で開始し STRING_TAIL で終了する
+HEAD <式> (MIDDLE <式>)* TAIL の順にトークンが並ぶ
+*/
+func (p *Parser) parseInterpolatedStringExpression() ast.Expression {
+	exp := &ast.InterpolatedStringExpression{Token: p.curToken, Parts: []ast.Expression{}}
+	for {
+		// 空の文字列部分は Parts に含めない
+		if p.curToken.Literal != "" {
+			exp.Parts = append(exp.Parts, &ast.StringLiteralExpression{Token: p.curToken, Value: p.curToken.Literal})
+		}
+		if p.curTokenIs(token.STRING_TAIL) {
+			return exp
+		}
+		p.nextToken()
+		exp.Parts = append(exp.Parts, p.parseExpression(LOWEST))
+		if p.peekTokenIs(token.STRING_MIDDLE) {
+			p.nextToken()
+		} else if !p.expectPeek(token.STRING_TAIL) {
+			return nil
+		}
+	}
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	pe := &ast.PrefixExpression{Token: p.curToken, Operator: p.curToken.Literal}
 	p.nextToken()                        // トークンを進めて式を読む
@@ -276,19 +562,96 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return ie
 }
 
+// 右結合の中置演算子（`2 ** 3 ** 2` は `2 ** (3 ** 2)`）
+// 右辺を 1 低い右結合力で解析することで、同じ演算子が続いたときに右辺が吸収する
+func (p *Parser) parseRightAssociativeInfixExpression(left ast.Expression) ast.Expression {
+	ie := &ast.InfixExpression{Token: p.curToken, Operator: p.curToken.Literal, Left: left}
+	precedence := p.curPrecedence()
+	p.nextToken()
+	ie.Right = p.parseExpression(precedence - 1)
+	return ie
+}
+
+// 代入は右結合なので右辺を ASSIGN より 1 低い右結合力で解析する（`a = b = 1` は `(a = (b = 1))`）
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	exp := &ast.AssignExpression{Token: p.curToken, Target: target, Operator: p.curToken.Literal}
+	switch target.(type) {
+	case *ast.IdentifierExpression, *ast.IndexExpression, *ast.MemberExpression: // x = v, xs[0] = v, h["k"] = v, h.k = v
+	default:
+		p.curTokenError(fmt.Sprintf("cannot assign to %s", target.String()))
+		return nil
+	}
+	p.nextToken()
+	exp.Value = p.parseExpression(ASSIGN - 1)
+	return exp
+}
+
 /*
-`(` で呼び出される。 expectPeek で `)` を飛ばす。
-つまり、`(` は構文解析時に除去され 専用の Expression はない。
+`(x + 1)` のようなグループ化
+`() => 1` や `(a, b) => a + b` のようにカンマを含む場合はラムダ式の仮引数リストとして読む
+（`(x) => x` は `x` を左側とする `=>` の中置演算として parseArrowFunctionExpression が読む）
 */
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	start := p.curToken
+	if p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		if !p.expectPeek(token.FAT_ARROW) {
+			return nil
+		}
+		return p.parseArrowFunctionBody(start.Pos, []*ast.IdentifierExpression{})
+	}
 	p.nextToken()
 	exp := p.parseExpression(LOWEST)
+	if p.peekTokenIs(token.COMMA) {
+		return p.parseArrowFunctionParameters(start, exp)
+	}
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
 	return exp
 }
 
+// (a, b, c) => body （curToken は最初の仮引数 a）
+func (p *Parser) parseArrowFunctionParameters(start token.Token, first ast.Expression) ast.Expression {
+	param, ok := first.(*ast.IdentifierExpression)
+	if !ok {
+		p.peekError(token.RPAREN) // `(1, 2)` はラムダ式の仮引数リストではない
+		return nil
+	}
+	params := []*ast.IdentifierExpression{param}
+	seen := map[string]bool{param.Value: true}
+	for p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		param := &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[param.Value] {
+			p.curTokenError(fmt.Sprintf("duplicate parameter %s", param.Value))
+			return nil
+		}
+		seen[param.Value] = true
+		params = append(params, param)
+	}
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+	return p.parseArrowFunctionBody(start.Pos, params)
+}
+
+// x => body （curToken は =>）
+func (p *Parser) parseArrowFunctionExpression(left ast.Expression) ast.Expression {
+	param, ok := left.(*ast.IdentifierExpression)
+	if !ok {
+		p.curTokenError(fmt.Sprintf("invalid arrow function parameter %s", left.String()))
+		return nil
+	}
+	return p.parseArrowFunctionBody(left.Pos(), []*ast.IdentifierExpression{param})
+}
+
 // TODO: elif を追加する
 func (p *Parser) parseIfExpression() ast.Expression {
 	exp := &ast.IfExpression{Token: p.curToken}
@@ -316,23 +679,96 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return exp
 }
 
+// try { ... } catch (e) { ... } finally { ... }
+func (p *Parser) parseTryExpression() ast.Expression {
+	exp := &ast.TryExpression{Token: p.curToken}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	exp.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		exp.Param = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		exp.Catch = p.parseBlockStatement()
+	}
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+		exp.Finally = p.parseBlockStatement()
+	}
+	if exp.Catch == nil && exp.Finally == nil {
+		p.addError(&ParseError{Pos: p.peekToken.Pos, Message: "expected catch or finally after try block", Expected: []token.TokenType{token.CATCH, token.FINALLY}, Found: p.peekToken})
+		return nil
+	}
+	return exp
+}
+
 func (p *Parser) parseFunctionExpression() ast.Expression {
 	fe := &ast.FunctionExpression{Token: p.curToken}
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
-	fe.Parameters = []*ast.IdentifierExpression{}
-	for _, p := range p.parseExpressionList(token.RPAREN) {
-		fe.Parameters = append(fe.Parameters, p.(*ast.IdentifierExpression))
+	if !p.parseFunctionParameters(fe) {
+		return nil
 	}
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
-	fe.Body = p.parseBlockStatement()
+	fe.Body = p.parseFunctionBody()
 	// 最後は curToken = } を指した状態で終了する
 	return fe
 }
 
+/*
+=> の後ろの式を本体とする FunctionExpression を作る（`fn(x) { x * 2 }` と同じ AST になる）
+本体は LOWEST で読むので `x => y => x + y` は右結合になる
+*/
+func (p *Parser) parseArrowFunctionBody(pos token.Position, params []*ast.IdentifierExpression) ast.Expression {
+	fe := &ast.FunctionExpression{Token: token.Token{Type: token.FUNCTION, Literal: "fn", Pos: pos}, Parameters: params}
+	p.nextToken()
+	tok := p.curToken
+	loops := p.loops
+	p.loops = 0
+	body := p.parseExpression(LOWEST)
+	p.loops = loops
+	fe.Body = &ast.BlockStatement{Token: tok, Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, ExpressionValue: body}}}
+	return fe
+}
+
+/*
+`x |> f(y)` を `f(x, y)` の CallExpression に脱糖する
+左結合なので `x |> f() |> g(1)` は `g(f(x), 1)` になる
+*/
+func (p *Parser) parsePipelineExpression(left ast.Expression) ast.Expression {
+	precedence := p.curPrecedence()
+	p.nextToken()
+	right := p.parseExpression(precedence)
+	call, ok := right.(*ast.CallExpression)
+	if !ok {
+		if _, bad := right.(*ast.BadExpression); !bad {
+			p.addError(&ParseError{Pos: right.Pos(), Message: fmt.Sprintf("right side of |> must be a call expression. got %s", right.String()), Found: p.curToken})
+		}
+		return nil
+	}
+	call.Arguments = append([]ast.Expression{left}, call.Arguments...)
+	return call
+}
+
 /*
 `(` の **infix** として登録される。
 これは add(x, y) や <fn(x, y){x+y}>(x, y) のように「式」のあとの中置演算 `(`（関数呼び出し）だからである。
@@ -350,31 +786,65 @@ func (p *Parser) parseArrayLiteralExpression() ast.Expression {
 	return array
 }
 
+// xs[i] もしくは xs[start:end] （start と end は省略できる）
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
-	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+	tok := p.curToken
+	var index ast.Expression
+	if !p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		index = p.parseExpression(LOWEST)
+	}
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		return p.parseSliceExpression(tok, left, index)
+	}
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
+	return &ast.IndexExpression{Token: tok, Left: left, Index: index}
+}
+
+// obj.field
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: left}
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	exp.Property = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
 	return exp
 }
 
+// curToken は `:`
+func (p *Parser) parseSliceExpression(tok token.Token, left, start ast.Expression) ast.Expression {
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: start}
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return exp
+}
+
+// `{` で開始し `}` で終了する（ペアの区切りには `,` が必要）
 func (p *Parser) parseHashLiteralExpression() ast.Expression {
-	exp := &ast.HashLiteralExpression{Token: p.curToken, Pairs: make(map[ast.Expression]ast.Expression)}
-	p.nextToken()
-	for !p.curTokenIs(token.RBRACE) {
+	exp := &ast.HashLiteralExpression{Token: p.curToken, Pairs: []ast.HashLiteralPair{}}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
 		key := p.parseExpression(LOWEST)
 		if !p.expectPeek(token.COLON) {
 			return nil
 		}
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
-		exp.Pairs[key] = value
-		if p.peekTokenIs(token.COMMA) {
-			p.nextToken()
+		exp.Pairs = append(exp.Pairs, ast.HashLiteralPair{Key: key, Value: value})
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
 		}
-		p.nextToken()
+	}
+	if !p.expectPeek(token.RBRACE) {
+		return nil
 	}
 	return exp
 }
@@ -384,17 +854,26 @@ func (p *Parser) parseMacroExpression() ast.Expression {
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
-	macro.Parameters = []*ast.IdentifierExpression{}
-	for _, p := range p.parseExpressionList(token.RPAREN) {
-		macro.Parameters = append(macro.Parameters, p.(*ast.IdentifierExpression))
+	params, ok := p.parseParameters()
+	if !ok {
+		return nil
 	}
+	macro.Parameters = params
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
-	macro.Body = p.parseBlockStatement()
+	macro.Body = p.parseFunctionBody()
 	return macro
 }
 
+// 関数の本体から外側のループを break / continue することはできない
+func (p *Parser) parseFunctionBody() *ast.BlockStatement {
+	loops := p.loops
+	p.loops = 0
+	defer func() { p.loops = loops }()
+	return p.parseBlockStatement()
+}
+
 func (p *Parser) parseExpressionList(endToken token.TokenType) []ast.Expression {
 	list := []ast.Expression{}
 	p.nextToken()
@@ -403,6 +882,7 @@ func (p *Parser) parseExpressionList(endToken token.TokenType) []ast.Expression
 		if p.peekTokenIs(token.COMMA) {
 			p.nextToken()
 		} else if !p.peekTokenIs(endToken) {
+			p.peekError(endToken)
 			return nil
 		}
 		p.nextToken()
@@ -410,6 +890,76 @@ func (p *Parser) parseExpressionList(endToken token.TokenType) []ast.Expression
 	return list
 }
 
+/*
+関数の仮引数リスト `(x, y = 2, ...rest)` を解析する
+- デフォルト値をもつ仮引数の後ろに、デフォルト値をもたない仮引数は書けない
+- 残余引数 `...rest` は最後にひとつだけ書ける
+curToken = ( で開始し、curToken = ) で終了する
+*/
+func (p *Parser) parseFunctionParameters(fe *ast.FunctionExpression) bool {
+	fe.Parameters = []*ast.IdentifierExpression{}
+	seen := map[string]bool{}
+	for !p.peekTokenIs(token.RPAREN) {
+		if p.peekTokenIs(token.ELLIPSIS) {
+			p.nextToken()
+			if !p.expectPeek(token.IDENTIFIER) {
+				return false
+			}
+			fe.Rest = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
+			if seen[fe.Rest.Value] {
+				p.curTokenError(fmt.Sprintf("duplicate parameter %s", fe.Rest.Value))
+				return false
+			}
+			return p.expectPeek(token.RPAREN)
+		}
+
+		if !p.expectPeek(token.IDENTIFIER) {
+			return false
+		}
+		param := &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[param.Value] {
+			p.curTokenError(fmt.Sprintf("duplicate parameter %s", param.Value))
+			return false
+		}
+		seen[param.Value] = true
+		fe.Parameters = append(fe.Parameters, param)
+
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			if fe.Defaults == nil {
+				fe.Defaults = map[string]ast.Expression{}
+			}
+			fe.Defaults[param.Value] = p.parseExpression(LOWEST)
+		} else if len(fe.Defaults) > 0 {
+			p.curTokenError(fmt.Sprintf("parameter %s without a default value follows a parameter with a default value", param.Value))
+			return false
+		}
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return false
+		}
+	}
+	p.nextToken()
+	return true
+}
+
+// curToken = `(` で開始し `)` で終了する仮引数の列（識別子のみ。マクロで使う）
+func (p *Parser) parseParameters() ([]*ast.IdentifierExpression, bool) {
+	params := []*ast.IdentifierExpression{}
+	for !p.peekTokenIs(token.RPAREN) {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil, false
+		}
+		params = append(params, &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal})
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil, false
+		}
+	}
+	p.nextToken()
+	return params, true
+}
+
 // ----------------------------------------------------------------------------
 // ----------------------------------------------------------------------------
 
@@ -446,24 +996,26 @@ func (p *Parser) registerInfixFn(tokenType token.TokenType, fn infixParseFn) {
 }
 
 // `)` は優先順位が最低=LOWESTとなる
-func getPrecedence(t token.TokenType) int {
-	if p, ok := precedences[t]; ok {
-		return p
+func (p *Parser) getPrecedence(t token.TokenType) int {
+	if precedence, ok := p.precedences[t]; ok {
+		return precedence
 	}
 	return LOWEST
 }
-func (p *Parser) curPrecedence() int  { return getPrecedence(p.curToken.Type) }
-func (p *Parser) peekPrecedence() int { return getPrecedence(p.peekToken.Type) }
+func (p *Parser) curPrecedence() int  { return p.getPrecedence(p.curToken.Type) }
+func (p *Parser) peekPrecedence() int { return p.getPrecedence(p.peekToken.Type) }
 
-func (p *Parser) Errors() []string {
-	return p.errors
-}
 func (p *Parser) peekError(t token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s instead.", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.addError(&ParseError{Pos: p.peekToken.Pos, Message: msg, Expected: []token.TokenType{t}, Found: p.peekToken})
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 	msg := fmt.Sprintf("no prefix parse function for %s", t)
-	p.errors = append(p.errors, msg)
+	p.addError(&ParseError{Pos: p.curToken.Pos, Message: msg, Found: p.curToken})
+}
+
+// curToken の解析に失敗したときのエラー
+func (p *Parser) curTokenError(msg string) {
+	p.addError(&ParseError{Pos: p.curToken.Pos, Message: msg, Found: p.curToken})
 }
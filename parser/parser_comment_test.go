@@ -0,0 +1,77 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ganyariya/go_monkey/ast"
+)
+
+func TestCommentsAttachedToStatements(t *testing.T) {
+	input := `// 足し算をする関数
+// 2 つの引数をとる
+let add = fn(x, y) {
+	// 本体
+	x + y
+};
+let a = add(1, /* inline */ 2); // trailing
+
+/* ブロックコメント */
+let b = a;
+// 末尾のコメント
+`
+	_, program := initParserProgram(t, input)
+	if len(program.Statements) != 3 {
+		t.Fatalf("program.Statements does not contain 3 statements. got=%d", len(program.Statements))
+	}
+
+	add := program.Statements[0].(*ast.LetStatement)
+	if len(add.LeadingComments()) != 2 {
+		t.Fatalf("add does not have 2 comments. got=%d", len(add.LeadingComments()))
+	}
+	if add.Doc() != "足し算をする関数\n2 つの引数をとる" {
+		t.Errorf("add.Doc() wrong. got=%q", add.Doc())
+	}
+
+	body := add.Value.(*ast.FunctionExpression).Body.Statements[0]
+	if len(body.LeadingComments()) != 1 || body.LeadingComments()[0].Text() != "本体" {
+		t.Errorf("body comment wrong. got=%v", body.LeadingComments())
+	}
+
+	// `/* inline */` は文の途中にあるため次の文に付与される
+	a := program.Statements[1].(*ast.LetStatement)
+	if len(a.LeadingComments()) != 0 {
+		t.Errorf("a should not have comments. got=%d", len(a.LeadingComments()))
+	}
+
+	b := program.Statements[2].(*ast.LetStatement)
+	comments := b.LeadingComments()
+	if len(comments) != 3 {
+		t.Fatalf("b does not have 3 comments. got=%d", len(comments))
+	}
+	if comments[0].Text() != "inline" || comments[1].Text() != "trailing" || !comments[1].Trailing {
+		t.Errorf("b comments wrong. got=%q, %q(trailing=%t)", comments[0].Text(), comments[1].Text(), comments[1].Trailing)
+	}
+	if b.Doc() != "ブロックコメント" {
+		t.Errorf("b.Doc() wrong. got=%q", b.Doc())
+	}
+
+	if len(program.Comments) != 1 || program.Comments[0].Text() != "末尾のコメント" {
+		t.Errorf("program.Comments wrong. got=%v", program.Comments)
+	}
+}
+
+func TestLetDocRequiresAdjacentComment(t *testing.T) {
+	input := `let a = 1; // a の説明ではない
+let b = 2;
+// 空行があるので c のドキュメントではない
+
+let c = 3;`
+	_, program := initParserProgram(t, input)
+
+	for _, stmt := range program.Statements[1:] {
+		let := stmt.(*ast.LetStatement)
+		if let.Doc() != "" {
+			t.Errorf("%s.Doc() should be empty. got=%q", let.Name.Value, let.Doc())
+		}
+	}
+}
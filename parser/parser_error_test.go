@@ -0,0 +1,272 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/lexer"
+	"github.com/ganyariya/go_monkey/token"
+)
+
+func TestParserErrorPosition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let = 5;", "main.monkey:1:5: expected next token to be IDENTIFIER, got ASSIGN instead."},
+		{"let x = 5;\nlet y 10;", "main.monkey:2:7: expected next token to be ASSIGN, got INT instead."},
+		{"1 +\n  ;", "main.monkey:2:3: no prefix parse function for SEMICOLON"},
+	}
+
+	for _, tt := range tests {
+		p := NewParser(lexer.NewLexerWithFilename("main.monkey", tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Fatalf("no parser errors for %q", tt.input)
+		}
+		if errors[0] != tt.expected {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expected, errors[0])
+		}
+	}
+}
+
+func TestIntegerLiteralErrors(t *testing.T) {
+	tests := []string{"0b12", "1__0", "0x", "1_"}
+
+	for _, input := range tests {
+		expected := "1:1: could not parse \"" + input + "\" as integer"
+		if errors := checkFirstParserError(t, input, expected); len(errors) != 1 {
+			t.Errorf("expected exactly 1 error for %q. got=%q", input, errors)
+		}
+	}
+}
+
+func TestParseErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		// 1 行のエラーが後続のエラーを連鎖させない
+		{"let = 5; let y = 10;", []string{"1:5: expected next token to be IDENTIFIER, got ASSIGN instead."}},
+		{"let x 5 + 3 * 2; let y = 10; y", []string{"1:7: expected next token to be ASSIGN, got INT instead."}},
+		{"1 + * 2 / ) ; let z = 1;", []string{"1:5: no prefix parse function for ASTERISK"}},
+		// ブロックの中のエラーはブロックの中で回復する
+		{"let f = fn(x) { let = 1; x }; let g = fn(y) { y + }; f(1)", []string{
+			"1:21: expected next token to be IDENTIFIER, got ASSIGN instead.",
+			"1:51: no prefix parse function for RBRACE",
+		}},
+		{"if (x +) { return 1; } let a = 1;", []string{"1:8: no prefix parse function for RPAREN"}},
+		{"let h = {1 2}; h", []string{"1:12: expected next token to be COLON, got INT instead."}},
+		{"let h = {1: 2 3: 4}; h", []string{"1:15: expected next token to be COMMA, got INT instead."}},
+		{"add(1 2); 3", []string{"1:7: expected next token to be RPAREN, got INT instead."}},
+		{"fn(x, 1) { x }", []string{"1:7: expected next token to be IDENTIFIER, got INT instead."}},
+		{"fn() { 1", []string{"1:9: expected next token to be RBRACE, got EOF instead."}},
+		{`let s = "abc`, []string{"1:9: unterminated string literal"}},
+	}
+
+	for _, tt := range tests {
+		p := NewParser(lexer.NewLexer(tt.input))
+		p.ParseProgram()
+		errors := p.Errors()
+		if len(errors) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q. expected=%q, got=%q", tt.input, tt.expected, errors)
+			continue
+		}
+		for i := range errors {
+			if errors[i] != tt.expected[i] {
+				t.Errorf("wrong error for %q. expected=%q, got=%q", tt.input, tt.expected[i], errors[i])
+			}
+		}
+	}
+}
+
+func TestPartialProgramWithBadNodes(t *testing.T) {
+	input := `let a = 1;
+let = 2;
+let b = 3 + ;
+let c = a;`
+	p := NewParser(lexer.NewLexer(input))
+	program := p.ParseProgram()
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program does not contain 4 statements. got=%d (%s)", len(program.Statements), program.String())
+	}
+	if _, ok := program.Statements[1].(*ast.BadStatement); !ok {
+		t.Errorf("program.Statements[1] is not BadStatement. got=%T", program.Statements[1])
+	}
+	letB, ok := program.Statements[2].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("program.Statements[2] is not LetStatement. got=%T", program.Statements[2])
+	}
+	infix, ok := letB.Value.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("letB.Value is not InfixExpression. got=%T", letB.Value)
+	}
+	if _, ok := infix.Right.(*ast.BadExpression); !ok {
+		t.Errorf("infix.Right is not BadExpression. got=%T", infix.Right)
+	}
+	checkIsValidLetStatement(t, program.Statements[3], "c", "a")
+
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 2 {
+		t.Fatalf("wrong number of diagnostics. got=%d", len(diagnostics))
+	}
+	first := diagnostics[0]
+	if first.Pos.Line != 2 || first.Pos.Column != 5 || first.Severity != SeverityError {
+		t.Errorf("wrong diagnostic position/severity. got=%s(%s)", first.Pos, first.Severity)
+	}
+	if len(first.Expected) != 1 || first.Expected[0] != token.IDENTIFIER || first.Found.Type != token.ASSIGN {
+		t.Errorf("wrong expected/found. got=%v/%v", first.Expected, first.Found)
+	}
+	if diagnostics[1].Found.Type != token.SEMICOLON || len(diagnostics[1].Expected) != 0 {
+		t.Errorf("wrong found token. got=%v", diagnostics[1].Found)
+	}
+}
+
+func TestParseWarnings(t *testing.T) {
+	p := NewParser(lexer.NewLexer("017 + 0o17"))
+	p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("warnings should not be reported as errors. got=%q", p.Errors())
+	}
+	diagnostics := p.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Severity != SeverityWarning {
+		t.Fatalf("expected 1 warning. got=%v", diagnostics)
+	}
+	expected := `1:1: warning: "017" is parsed as an octal literal; use the 0o prefix to make it explicit`
+	if diagnostics[0].Error() != expected {
+		t.Errorf("wrong warning. expected=%q, got=%q", expected, diagnostics[0].Error())
+	}
+}
+
+func TestAssignExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 = 2", "1:3: cannot assign to 1"},
+		{"f(x) += 1", "1:6: cannot assign to f(x)"},
+		{"a + b = c", "1:7: cannot assign to (a + b)"},
+		{"xs[1:2] = c", "1:9: cannot assign to (xs[1:2])"},
+		{"a.f() = c", "1:7: cannot assign to (a.f)()"},
+	}
+
+	for _, tt := range tests {
+		if errors := checkFirstParserError(t, tt.input, tt.expected); len(errors) != 1 {
+			t.Errorf("expected exactly 1 error for %q. got=%q", tt.input, errors)
+		}
+	}
+}
+
+func TestLoopErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"break;", "1:1: break outside loop"},
+		{"if (true) { continue }", "1:13: continue outside loop"},
+		{"while (true) { fn() { break; } }", "1:23: break outside loop"},
+		{"for (x of xs) { x }", "1:8: expected next token to be IN, got IDENTIFIER instead."},
+		{"for (1 in xs) { x }", "1:6: expected next token to be IDENTIFIER, got INT instead."},
+		{"while true { x }", "1:7: expected next token to be LPAREN, got TRUE instead."},
+	}
+
+	for _, tt := range tests {
+		if errors := checkFirstParserError(t, tt.input, tt.expected); len(errors) != 1 {
+			t.Errorf("expected exactly 1 error for %q. got=%q", tt.input, errors)
+		}
+	}
+}
+
+func TestFunctionParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(x = 1, y) {}", "1:11: parameter y without a default value follows a parameter with a default value"},
+		{"fn(...rest, x) {}", "1:11: expected next token to be RPAREN, got COMMA instead."},
+		{"fn(x, x) {}", "1:7: duplicate parameter x"},
+		{"fn(x, ...x) {}", "1:10: duplicate parameter x"},
+		{"fn(...) {}", "1:7: expected next token to be IDENTIFIER, got RPAREN instead."},
+		{"fn(x = ) {}", "1:8: no prefix parse function for RPAREN"},
+	}
+
+	for _, tt := range tests {
+		if errors := checkFirstParserError(t, tt.input, tt.expected); len(errors) != 1 {
+			t.Errorf("expected exactly 1 error for %q. got=%q", tt.input, errors)
+		}
+	}
+}
+
+func TestMatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { }", "1:13: match expression has no arms"},
+		{"match (x) { 1 + 2 => 3 }", "1:15: expected next token to be FAT_ARROW, got PLUS instead."},
+		{"match (x) { fn => 1 }", "1:13: unexpected FUNCTION in pattern"},
+		{"match (x) { -a => 1 }", "1:14: expected next token to be INT, got IDENTIFIER instead."},
+		{"match (x) { [...t, x] => 1 }", "1:18: expected next token to be RBRACKET, got COMMA instead."},
+		{"match (x) { {k: v} => 1 }", "1:14: unexpected IDENTIFIER in pattern"},
+		{"match (x) { 1 => 1 2 => 2 }", "1:20: expected next token to be COMMA, got INT instead."},
+	}
+
+	for _, tt := range tests {
+		checkFirstParserError(t, tt.input, tt.expected)
+	}
+}
+
+func TestPipelineAndArrowFunctionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x |> 1", "1:6: right side of |> must be a call expression. got 1"},
+		{"x |> f", "1:6: right side of |> must be a call expression. got f"},
+		{"1 => 2", "1:3: invalid arrow function parameter 1"},
+		{"a + b => 1", "1:7: invalid arrow function parameter (a + b)"},
+		{"(a, 1) => 2", "1:5: expected next token to be IDENTIFIER, got INT instead."},
+		{"(1, a) => 2", "1:3: expected next token to be RPAREN, got COMMA instead."},
+		{"(a, a) => 1", "1:5: duplicate parameter a"},
+		{"(a, b)", "1:7: expected next token to be FAT_ARROW, got EOF instead."},
+		{"() + 1", "1:4: expected next token to be FAT_ARROW, got PLUS instead."},
+	}
+
+	for _, tt := range tests {
+		checkFirstParserError(t, tt.input, tt.expected)
+	}
+}
+
+func TestMemberExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a.1", "1:3: expected next token to be IDENTIFIER, got INT instead."},
+		{"a.", "1:3: expected next token to be IDENTIFIER, got EOF instead."},
+		{".a", "1:1: no prefix parse function for DOT"},
+	}
+
+	for _, tt := range tests {
+		checkFirstParserError(t, tt.input, tt.expected)
+	}
+}
+
+func TestTryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { 1 }", "1:10: expected catch or finally after try block"},
+		{"try { 1 } catch { 2 }", "1:17: expected next token to be LPAREN, got LBRACE instead."},
+		{"try { 1 } catch () { 2 }", "1:18: expected next token to be IDENTIFIER, got RPAREN instead."},
+		{"try 1 catch (e) {}", "1:5: expected next token to be LBRACE, got INT instead."},
+	}
+
+	for _, tt := range tests {
+		checkFirstParserError(t, tt.input, tt.expected)
+	}
+}
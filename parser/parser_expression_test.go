@@ -23,6 +23,29 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	checkIsValidLiteralExpression(t, stmt.ExpressionValue, 5)
 }
 
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"1e-9;", 1e-9},
+		{"2.5E+3;", 2500},
+	}
+
+	for _, tt := range tests {
+		_, program := initParserProgram(t, tt.input)
+		stmt := checkIsExpressionStatements(t, program, 1)
+		literal, ok := stmt.ExpressionValue.(*ast.FloatLiteralExpression)
+		if !ok {
+			t.Fatalf("exp not *ast.FloatLiteralExpression. got=%T", stmt.ExpressionValue)
+		}
+		if literal.Value != tt.expected {
+			t.Fatalf("expected=%g, got=%g", tt.expected, literal.Value)
+		}
+	}
+}
+
 func TestBooleanExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -39,6 +62,18 @@ func TestBooleanExpression(t *testing.T) {
 	}
 }
 
+func TestNullLiteralExpression(t *testing.T) {
+	_, program := initParserProgram(t, "null;")
+	stmt := checkIsExpressionStatements(t, program, 1)
+	null, ok := stmt.ExpressionValue.(*ast.NullLiteralExpression)
+	if !ok {
+		t.Fatalf("exp is not *ast.NullLiteralExpression. got=%T", stmt.ExpressionValue)
+	}
+	if null.String() != "null" {
+		t.Fatalf("null.String() is not 'null'. got=%q", null.String())
+	}
+}
+
 func TestStringLiteralExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -61,6 +96,28 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestInterpolatedStringExpression(t *testing.T) {
+	input := `"hello ${name}, you are ${age + 1}!"`
+	_, program := initParserProgram(t, input)
+	stmt := checkIsExpressionStatements(t, program, 1)
+	exp, ok := stmt.ExpressionValue.(*ast.InterpolatedStringExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.InterpolatedStringExpression. got=%T", stmt.ExpressionValue)
+	}
+	if len(exp.Parts) != 5 {
+		t.Fatalf("len(Parts) not 5. got=%d", len(exp.Parts))
+	}
+	checkIsStringLiteralExpression(t, exp.Parts[0], "hello ")
+	checkIsIdentifierExpression(t, exp.Parts[1], "name")
+	checkIsStringLiteralExpression(t, exp.Parts[2], ", you are ")
+	checkIsValidInfixExpression(t, exp.Parts[3], "age", "+", 1)
+	checkIsStringLiteralExpression(t, exp.Parts[4], "!")
+
+	if exp.String() != "hello ${name}, you are ${(age + 1)}!" {
+		t.Errorf("exp.String() wrong. got=%q", exp.String())
+	}
+}
+
 func TestParsingPrefixExpressions(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -236,6 +293,49 @@ func TestFunctionExpressionParametersParsing(t *testing.T) {
 	}
 }
 
+func TestFunctionExpressionDefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		rest     string
+	}{
+		{"fn(x, y = 2) { x + y }", "fn(x, y = 2)(x + y)", ""},
+		{"fn(x = 1, y = x * 2) {}", "fn(x = 1, y = (x * 2))", ""},
+		{"fn(...args) {}", "fn(...args)", "args"},
+		{"fn(first, ...rest) {}", "fn(first, ...rest)", "rest"},
+		{"fn(a, b = [1, 2], ...rest) {}", "fn(a, b = [1, 2], ...rest)", "rest"},
+	}
+	for _, tt := range tests {
+		_, program := initParserProgram(t, tt.input)
+		stmt := checkIsExpressionStatements(t, program, 1)
+		function, ok := stmt.ExpressionValue.(*ast.FunctionExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.FunctionExpression. got=%T", stmt.ExpressionValue)
+		}
+		if function.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, function.String())
+		}
+		if tt.rest == "" && function.Rest != nil {
+			t.Errorf("function.Rest is not nil. got=%s", function.Rest)
+		}
+		if tt.rest != "" {
+			checkIsIdentifierExpression(t, function.Rest, tt.rest)
+		}
+	}
+}
+
+func TestFunctionNameFromLetStatement(t *testing.T) {
+	_, program := initParserProgram(t, "let add = fn(x, y) { x + y }; let f = add; fn() {}")
+	function := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionExpression)
+	if function.Name != "add" {
+		t.Errorf("function.Name is not %q. got=%q", "add", function.Name)
+	}
+	anonymous := program.Statements[2].(*ast.ExpressionStatement).ExpressionValue.(*ast.FunctionExpression)
+	if anonymous.Name != "" {
+		t.Errorf("anonymous function has name %q", anonymous.Name)
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	input := `add(1, 2 * 3, 4 + 5);`
 	_, program := initParserProgram(t, input)
@@ -300,3 +400,217 @@ func TestParsingArrayIndexExpressions(t *testing.T) {
 		return
 	}
 }
+
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		start    interface{}
+		end      interface{}
+		expected string
+	}{
+		{"xs[1:3]", 1, 3, "(xs[1:3])"},
+		{"xs[:2]", nil, 2, "(xs[:2])"},
+		{"xs[-2:]", nil, nil, "(xs[(-2):])"},
+		{"xs[:]", nil, nil, "(xs[:])"},
+		{"xs[i + 1:n]", nil, "n", "(xs[(i + 1):n])"},
+	}
+	for _, tt := range tests {
+		_, program := initParserProgram(t, tt.input)
+		stmt := checkIsExpressionStatements(t, program, 1)
+		exp, ok := stmt.ExpressionValue.(*ast.SliceExpression)
+		if !ok {
+			t.Fatalf("exp not SliceExpression. got=%T", stmt.ExpressionValue)
+		}
+		checkIsIdentifierExpression(t, exp.Left, "xs")
+		if tt.start != nil {
+			checkIsValidLiteralExpression(t, exp.Start, tt.start)
+		}
+		if tt.end != nil {
+			checkIsValidLiteralExpression(t, exp.End, tt.end)
+		}
+		if exp.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, exp.String())
+		}
+	}
+}
+
+func TestAssignExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		name     string
+		operator string
+		value    interface{}
+	}{
+		{"x = 5;", "x", "=", 5},
+		{"x += 1", "x", "+=", 1},
+		{"y -= z;", "y", "-=", "z"},
+		{"y *= 2", "y", "*=", 2},
+		{"y /= 2", "y", "/=", 2},
+	}
+
+	for _, tt := range tests {
+		_, program := initParserProgram(t, tt.input)
+		stmt := checkIsExpressionStatements(t, program, 1)
+
+		exp, ok := stmt.ExpressionValue.(*ast.AssignExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.AssignExpression. got=%T", stmt.ExpressionValue)
+		}
+		checkIsIdentifierExpression(t, exp.Target, tt.name)
+		if exp.Operator != tt.operator {
+			t.Errorf("exp.Operator is not %q. got=%q", tt.operator, exp.Operator)
+		}
+		checkIsValidLiteralExpression(t, exp.Value, tt.value)
+	}
+}
+
+func TestIndexAssignExpression(t *testing.T) {
+	_, program := initParserProgram(t, `h["k"] += xs[0] = 1`)
+	stmt := checkIsExpressionStatements(t, program, 1)
+	exp, ok := stmt.ExpressionValue.(*ast.AssignExpression)
+	if !ok {
+		t.Fatalf("exp is not ast.AssignExpression. got=%T", stmt.ExpressionValue)
+	}
+	if _, ok := exp.Target.(*ast.IndexExpression); !ok {
+		t.Fatalf("exp.Target is not ast.IndexExpression. got=%T", exp.Target)
+	}
+	expected := "((h[k]) += ((xs[0]) = 1))"
+	if exp.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, exp.String())
+	}
+}
+
+func TestWhileStatement(t *testing.T) {
+	_, program := initParserProgram(t, "while (x < 10) { x += 1; if (x == 5) { break; } continue }")
+	if len(program.Statements) != 1 {
+		t.Fatalf("program does not contain 1 statement. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.WhileStatement. got=%T", program.Statements[0])
+	}
+	checkIsValidInfixExpression(t, stmt.Condition, "x", "<", 10)
+	if len(stmt.Body.Statements) != 3 {
+		t.Fatalf("body does not contain 3 statements. got=%d", len(stmt.Body.Statements))
+	}
+	if _, ok := stmt.Body.Statements[2].(*ast.ContinueStatement); !ok {
+		t.Errorf("body.Statements[2] is not ast.ContinueStatement. got=%T", stmt.Body.Statements[2])
+	}
+	expected := "while(x < 10) (x += 1)if(x == 5) break;continue;"
+	if stmt.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, stmt.String())
+	}
+}
+
+func TestForStatement(t *testing.T) {
+	_, program := initParserProgram(t, `for (ch in "abc") { puts(ch); } 1`)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program does not contain 2 statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ForStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.ForStatement. got=%T", program.Statements[0])
+	}
+	checkIsIdentifierExpression(t, stmt.Variable, "ch")
+	checkIsStringLiteralExpression(t, stmt.Iterable, "abc")
+	if len(stmt.Body.Statements) != 1 {
+		t.Fatalf("body does not contain 1 statement. got=%d", len(stmt.Body.Statements))
+	}
+}
+
+func TestMatchExpression(t *testing.T) {
+	input := `match (v) { 0 => "zero", -1.5 => "neg", [h, _, ...t] => h, {"type": t, 1: [x]} if t == "a" => x, n => n, }`
+	_, program := initParserProgram(t, input)
+	stmt := checkIsExpressionStatements(t, program, 1)
+	exp, ok := stmt.ExpressionValue.(*ast.MatchExpression)
+	if !ok {
+		t.Fatalf("exp is not ast.MatchExpression. got=%T", stmt.ExpressionValue)
+	}
+	checkIsIdentifierExpression(t, exp.Subject, "v")
+	if len(exp.Arms) != 5 {
+		t.Fatalf("match does not contain 5 arms. got=%d", len(exp.Arms))
+	}
+
+	if _, ok := exp.Arms[0].Pattern.(*ast.LiteralPattern); !ok {
+		t.Errorf("arms[0].Pattern is not ast.LiteralPattern. got=%T", exp.Arms[0].Pattern)
+	}
+	array, ok := exp.Arms[2].Pattern.(*ast.ArrayPattern)
+	if !ok {
+		t.Fatalf("arms[2].Pattern is not ast.ArrayPattern. got=%T", exp.Arms[2].Pattern)
+	}
+	if _, ok := array.Elements[1].(*ast.WildcardPattern); !ok {
+		t.Errorf("array.Elements[1] is not ast.WildcardPattern. got=%T", array.Elements[1])
+	}
+	checkIsIdentifierExpression(t, array.Rest, "t")
+	if _, ok := exp.Arms[3].Pattern.(*ast.HashPattern); !ok {
+		t.Errorf("arms[3].Pattern is not ast.HashPattern. got=%T", exp.Arms[3].Pattern)
+	}
+	if exp.Arms[3].Guard.String() != "(t == a)" {
+		t.Errorf("arms[3].Guard is not %q. got=%q", "(t == a)", exp.Arms[3].Guard.String())
+	}
+	if _, ok := exp.Arms[4].Pattern.(*ast.BindingPattern); !ok {
+		t.Errorf("arms[4].Pattern is not ast.BindingPattern. got=%T", exp.Arms[4].Pattern)
+	}
+
+	expected := "match (v) { 0 => zero, (-1.5) => neg, [h, _, ...t] => h, {type:t, 1:[x]} if (t == a) => x, n => n }"
+	if exp.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, exp.String())
+	}
+}
+
+func TestMatchGuardIsNotArrowFunction(t *testing.T) {
+	_, program := initParserProgram(t, "match (x) { n if ok => n, _ => y => y }")
+	stmt := checkIsExpressionStatements(t, program, 1)
+	exp := stmt.ExpressionValue.(*ast.MatchExpression)
+	checkIsIdentifierExpression(t, exp.Arms[0].Guard, "ok")
+	checkIsIdentifierExpression(t, exp.Arms[0].Body, "n")
+	if _, ok := exp.Arms[1].Body.(*ast.FunctionExpression); !ok {
+		t.Errorf("arms[1].Body is not ast.FunctionExpression. got=%T", exp.Arms[1].Body)
+	}
+}
+
+func TestTryExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		param    string
+		catch    bool
+		finally  bool
+		expected string
+	}{
+		{"try { f() } catch (e) { e }", "e", true, false, "try f() catch (e) e"},
+		{"try { f() } finally { g() }", "", false, true, "try f() finally g()"},
+		{"try { f() } catch (err) { 1 } finally { g() }", "err", true, true, "try f() catch (err) 1 finally g()"},
+	}
+	for _, tt := range tests {
+		_, program := initParserProgram(t, tt.input)
+		stmt := checkIsExpressionStatements(t, program, 1)
+		exp, ok := stmt.ExpressionValue.(*ast.TryExpression)
+		if !ok {
+			t.Fatalf("exp is not ast.TryExpression. got=%T", stmt.ExpressionValue)
+		}
+		if tt.catch {
+			checkIsIdentifierExpression(t, exp.Param, tt.param)
+		}
+		if (exp.Catch != nil) != tt.catch || (exp.Finally != nil) != tt.finally {
+			t.Errorf("wrong clauses. catch=%v, finally=%v", exp.Catch != nil, exp.Finally != nil)
+		}
+		if exp.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, exp.String())
+		}
+	}
+}
+
+func TestThrowStatement(t *testing.T) {
+	_, program := initParserProgram(t, `throw "boom"; throw {"kind": "E"}`)
+	if len(program.Statements) != 2 {
+		t.Fatalf("program does not contain 2 statements. got=%d", len(program.Statements))
+	}
+	stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("stmt is not ast.ThrowStatement. got=%T", program.Statements[0])
+	}
+	checkIsStringLiteralExpression(t, stmt.Value, "boom")
+	if stmt.String() != "throw boom;" {
+		t.Errorf("expected=%q, got=%q", "throw boom;", stmt.String())
+	}
+}
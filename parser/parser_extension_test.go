@@ -0,0 +1,94 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/lexer"
+	"github.com/ganyariya/go_monkey/token"
+)
+
+func TestRegisterOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`a =~ "x"`, "(a =~ x)"},
+		{"1 .. 2 + 3", "(1 .. (2 + 3))"},
+		{"1 .. 2 == 3 .. 4", "((1 .. 2) == (3 .. 4))"},
+		{"1 ... 2", "(1 ... 2)"},
+		{"√x * 2", "((√x) * 2)"},
+		{"a mod b + c", "(a mod (b + c))"},
+		{"a == b", "(a == b)"},
+	}
+
+	for _, tt := range tests {
+		p := NewParser(lexer.NewLexer(tt.input))
+		p.RegisterInfixOperator("=~", "MATCH", EQUALS)
+		p.RegisterInfixOperator("..", "RANGE", SUM-1)
+		p.RegisterInfixOperator("...", "RANGE_INCLUSIVE", SUM-1)
+		p.RegisterInfixOperator("mod", "MOD", LOWEST+1)
+		p.RegisterPrefixOperator("√", "SQRT")
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+// 登録していないパーサーには影響しない
+func TestRegisterOperatorsArePerParser(t *testing.T) {
+	p := NewParser(lexer.NewLexer("a .. b"))
+	p.RegisterInfixOperator("..", "RANGE", SUM)
+	p.ParseProgram()
+	checkParserErrors(t, p)
+
+	p = NewParser(lexer.NewLexer("a .. b"))
+	p.ParseProgram()
+	if len(p.Errors()) == 0 {
+		t.Errorf("expected errors for unregistered operator")
+	}
+}
+
+func TestRegisterCustomParseFunction(t *testing.T) {
+	// `a ? b : c` を if 式として解析する
+	input := "let x = a ? b : c;"
+	p := NewParser(lexer.NewLexer(input))
+	p.RegisterToken("?", "QUESTION")
+	p.RegisterInfix("QUESTION", EQUALS-1, func(condition ast.Expression) ast.Expression {
+		exp := &ast.IfExpression{Token: p.CurToken(), Condition: condition}
+		p.NextToken()
+		consequence := p.ParseExpression(LOWEST)
+		if !p.ExpectPeek(token.COLON) {
+			return nil
+		}
+		p.NextToken()
+		alternative := p.ParseExpression(LOWEST)
+		exp.Consequence = &ast.BlockStatement{Statements: []ast.Statement{&ast.ExpressionStatement{ExpressionValue: consequence}}}
+		exp.Alternative = &ast.BlockStatement{Statements: []ast.Statement{&ast.ExpressionStatement{ExpressionValue: alternative}}}
+		return exp
+	})
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expected := "let x = ifa belse c;"
+	if program.String() != expected {
+		t.Errorf("expected=%q, got=%q", expected, program.String())
+	}
+
+	p = NewParser(lexer.NewLexer("a ? b c"))
+	p.RegisterToken("?", "QUESTION")
+	p.RegisterInfix("QUESTION", EQUALS-1, func(condition ast.Expression) ast.Expression {
+		p.NextToken()
+		p.ParseExpression(LOWEST)
+		p.ExpectPeek(token.COLON)
+		return nil
+	})
+	p.ParseProgram()
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "1:7: expected next token to be COLON, got IDENTIFIER instead." {
+		t.Errorf("wrong errors. got=%q", errors)
+	}
+}
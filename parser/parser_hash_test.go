@@ -23,7 +23,8 @@ func TestParsingHashLiteralStringKeys(t *testing.T) {
 		"two":   2,
 		"three": 3,
 	}
-	for ke, ve := range exp.Pairs {
+	for _, pair := range exp.Pairs {
+		ke, ve := pair.Key, pair.Value
 		literal, ok := ke.(*ast.StringLiteralExpression)
 		if !ok {
 			t.Fatalf("hash's key not StringLiteral. got=%T", ke)
@@ -62,7 +63,8 @@ func TestParsingHashLiteralIntegerKeys(t *testing.T) {
 		2: "two",
 		3: "three",
 	}
-	for ke, ve := range exp.Pairs {
+	for _, pair := range exp.Pairs {
+		ke, ve := pair.Key, pair.Value
 		literal, ok := ke.(*ast.IntegerLiteralExpression)
 		if !ok {
 			t.Fatalf("hash's key not IntegerLiteral. got=%T", ke)
@@ -88,7 +90,8 @@ func TestParsingHashLiteralBooleanKeys(t *testing.T) {
 		true:  "true",
 		false: "false",
 	}
-	for ke, ve := range exp.Pairs {
+	for _, pair := range exp.Pairs {
+		ke, ve := pair.Key, pair.Value
 		literal, ok := ke.(*ast.BooleanExpression)
 		if !ok {
 			t.Fatalf("hash's key not IntegerLiteral. got=%T", ke)
@@ -121,7 +124,8 @@ func TestParsingHashLiteralWithExpressions(t *testing.T) {
 			checkIsValidInfixExpression(t, e, 15, "/", 5)
 		},
 	}
-	for ke, ve := range exp.Pairs {
+	for _, pair := range exp.Pairs {
+		ke, ve := pair.Key, pair.Value
 		literal, ok := ke.(*ast.StringLiteralExpression)
 		if !ok {
 			t.Fatalf("hash's key not StringLiteral. got=%T", ke)
@@ -134,3 +138,17 @@ func TestParsingHashLiteralWithExpressions(t *testing.T) {
 	}
 
 }
+
+func TestParsingHashLiteralKeepsOrder(t *testing.T) {
+	input := `{"b": 1, "a": 2, 3: "c", true: 4}`
+	_, program := initParserProgram(t, input)
+	stmt := checkIsExpressionStatements(t, program, 1)
+	exp, ok := stmt.ExpressionValue.(*ast.HashLiteralExpression)
+	if !ok {
+		t.Fatalf("exp not HashLiteralExpression. got=%T", stmt.ExpressionValue)
+	}
+	expected := `{b:1, a:2, 3:c, true:4}`
+	if exp.String() != expected {
+		t.Fatalf("exp.String() wrong. expected=%q, got=%q", expected, exp.String())
+	}
+}
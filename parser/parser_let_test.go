@@ -24,6 +24,34 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = pair;", "let [a, b] = pair;"},
+		{"let [first, _, ...rest] = xs", "let [first, _, ...rest] = xs;"},
+		{`let {"name": n, "age": a} = person;`, "let {name:n, age:a} = person;"},
+		{`let {"pos": [x, y]} = p;`, "let {pos:[x, y]} = p;"},
+	}
+	for _, tt := range tests {
+		_, program := initParserProgram(t, tt.input)
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("stmt is not LetStatement. got=%T", program.Statements[0])
+		}
+		if stmt.Name != nil {
+			t.Errorf("stmt.Name is not nil. got=%s", stmt.Name)
+		}
+		if stmt.Pattern == nil {
+			t.Fatalf("stmt.Pattern is nil")
+		}
+		if stmt.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, stmt.String())
+		}
+	}
+}
+
 func checkIsValidLetStatement(t *testing.T, stmt ast.Statement, name string, value interface{}) bool {
 	if stmt.TokenLiteral() != "let" {
 		t.Errorf("s.TokenLiteral not 'let', get=%q", stmt.TokenLiteral())
@@ -97,6 +97,55 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"add(a + b + c * d / f + g)", "add((((a + b) + ((c * d) / f)) + g))"},
 		{"a * [1, 2, 3, 4][b * c] * d", "((a * ([1, 2, 3, 4][(b * c)])) * d)"},
 		{"add(a * b[2], b[1], 2 * [1, 2][1])", "add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))"},
+		{"a % b * c", "((a % b) * c)"},
+		{"a + b % c", "(a + (b % c))"},
+		{"flags & 0x0F == 1", "((flags & 0x0F) == 1)"},
+		{"a | b ^ c & d", "(a | (b ^ (c & d)))"},
+		{"a & b | c", "((a & b) | c)"},
+		{"1 << 2 + 3", "(1 << (2 + 3))"},
+		{"a << 1 & b >> 2", "((a << 1) & (b >> 2))"},
+		{"x >> 1 < y", "((x >> 1) < y)"},
+		{"~a & b", "((~a) & b)"},
+		{"x = 1 + 2", "(x = (1 + 2))"},
+		{"a = b = c", "(a = (b = c))"},
+		{"x += y * 2 == 4", "(x += ((y * 2) == 4))"},
+		{"f(x -= 1)", "f((x -= 1))"},
+		{"a == b && c != d", "((a == b) && (c != d))"},
+		{"a is b == c", "((a is b) == c)"},
+		{"x + 1 is y && z", "(((x + 1) is y) && z)"},
+		{"a || b && c", "(a || (b && c))"},
+		{"a && b || c && d", "((a && b) || (c && d))"},
+		{"a || b || c", "((a || b) || c)"},
+		{"x = a || b", "(x = (a || b))"},
+		{"a & b && c | d", "((a & b) && (c | d))"},
+		{"!a && b < c", "((!a) && (b < c))"},
+		{"a <= b == c >= d", "((a <= b) == (c >= d))"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"-a ** b", "(-(a ** b))"},
+		{"a ** -b", "(a ** (-b))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"a ** b * c", "((a ** b) * c)"},
+		{"a ** f(b)[0]", "(a ** (f(b)[0]))"},
+		{"x |> f()", "f(x)"},
+		{"x |> f(y) |> g(z)", "g(f(x, y), z)"},
+		{"a + b |> f()", "f((a + b))"},
+		{"xs |> len() == 3", "(len(xs) == 3)"},
+		{"a |> f() || b", "(f(a) || b)"},
+		{"x |> fn(a, b) { a + b }(1)", "fn(a, b)(a + b)(x, 1)"},
+		{"x => x * 2", "fn(x)(x * 2)"},
+		{"(a, b) => a + b", "fn(a, b)(a + b)"},
+		{"() => 1", "fn()1"},
+		{"(x) => x", "fn(x)x"},
+		{"x => y => x + y", "fn(x)fn(y)(x + y)"},
+		{"f = x => x || y", "(f = fn(x)(x || y))"},
+		{"xs |> map(x => x * 2) |> sum()", "sum(map(xs, fn(x)(x * 2)))"},
+		{"map(xs, (a, b) => a, 1)", "map(xs, fn(a, b)a, 1)"},
+		{"a.b.c", "((a.b).c)"},
+		{"a.b[0].c", "(((a.b)[0]).c)"},
+		{"-a.b * c", "((-(a.b)) * c)"},
+		{"a.f(1).g", "((a.f)(1).g)"},
+		{"a.b = c + 1", "((a.b) = (c + 1))"},
+		{"x |> a.f()", "(a.f)(x)"},
 	}
 
 	for _, tt := range tests {
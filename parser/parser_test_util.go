@@ -28,6 +28,18 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	t.FailNow()
 }
 
+// input の最初の構文エラーが expected であることを確かめ、すべてのエラーを返す
+func checkFirstParserError(t *testing.T, input string, expected string) []string {
+	t.Helper()
+	p := NewParser(lexer.NewLexer(input))
+	p.ParseProgram()
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != expected {
+		t.Errorf("wrong errors for %q. expected=%q, got=%q", input, expected, errors)
+	}
+	return errors
+}
+
 // ------------------------------------------------------------------------------------------------------
 // ------------------------------------------------------------------------------------------------------
 
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/ganyariya/go_monkey/ast"
+	"github.com/ganyariya/go_monkey/token"
+)
+
+/*
+match (subject) { pattern => expr, pattern if guard => expr, ... }
+最後の分岐の後ろのカンマは省略できる
+*/
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken}
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+	exp.Subject = p.parseExpression(LOWEST)
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		exp.Arms = append(exp.Arms, arm)
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	if len(exp.Arms) == 0 {
+		p.curTokenError("match expression has no arms")
+		return nil
+	}
+	return exp
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{Pattern: p.parsePattern()}
+	if arm.Pattern == nil {
+		return nil
+	}
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		// `if ok => 1` の `ok => 1` をラムダ式として読まないように => の手前で止める
+		arm.Guard = p.parseExpression(ARROW)
+		if arm.Guard == nil {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+	p.nextToken()
+	arm.Body = p.parseExpression(LOWEST)
+	if arm.Body == nil {
+		return nil
+	}
+	return arm
+}
+
+// curToken から始まるパターンを読む
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.IDENTIFIER:
+		if p.curToken.Literal == "_" {
+			return &ast.WildcardPattern{Token: p.curToken}
+		}
+		return &ast.BindingPattern{Name: &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}}
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE, token.NULL, token.MINUS:
+		value := p.parseLiteralPatternValue()
+		if value == nil {
+			return nil
+		}
+		return &ast.LiteralPattern{Value: value}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	}
+	p.curTokenError(fmt.Sprintf("unexpected %s in pattern", p.curToken.Type))
+	return nil
+}
+
+/*
+パターンに書けるリテラル（`1` `-2.5` `"str"` `true` `null`）
+`1 + 2` のような式は書けないので、前置の解析関数だけを呼び出す
+*/
+func (p *Parser) parseLiteralPatternValue() ast.Expression {
+	switch p.curToken.Type {
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE, token.NULL:
+		return p.prefixParseFns[p.curToken.Type]()
+	case token.MINUS:
+		tok := p.curToken
+		if !p.peekTokenIs(token.INT) && !p.peekTokenIs(token.FLOAT) {
+			p.peekError(token.INT)
+			return nil
+		}
+		p.nextToken()
+		right := p.prefixParseFns[p.curToken.Type]()
+		if right == nil {
+			return nil
+		}
+		return &ast.PrefixExpression{Token: tok, Operator: tok.Literal, Right: right}
+	}
+	p.curTokenError(fmt.Sprintf("unexpected %s in pattern", p.curToken.Type))
+	return nil
+}
+
+// [a, [b, _], ...rest]
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENTIFIER) {
+				return nil
+			}
+			pattern.Rest = &ast.IdentifierExpression{Token: p.curToken, Value: p.curToken.Literal}
+			break // rest は最後の要素でなければならない
+		}
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+	return pattern
+}
+
+// {"type": t, "value": [x, _]}
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken}
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+		key := p.parseLiteralPatternValue()
+		if key == nil {
+			return nil
+		}
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+		p.nextToken()
+		value := p.parsePattern()
+		if value == nil {
+			return nil
+		}
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+	return pattern
+}
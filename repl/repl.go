@@ -44,12 +44,20 @@ func Start(in io.Reader, out io.Writer) {
 
 		evaluated := evaluator.Eval(expanded, env)
 		if evaluated != nil {
-			io.WriteString(out, evaluated.Inspect())
+			io.WriteString(out, inspect(evaluated))
 			io.WriteString(out, "\n")
 		}
 	}
 }
 
+// エラーは関数呼び出しのトレースバックつきで表示する
+func inspect(obj object.Object) string {
+	if errObj, ok := obj.(*object.Error); ok {
+		return errObj.Traceback()
+	}
+	return obj.Inspect()
+}
+
 func printParseErrors(out io.Writer, errors []string) {
 	for _, msg := range errors {
 		io.WriteString(out, "\t"+msg+"\n")
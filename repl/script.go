@@ -0,0 +1,36 @@
+package repl
+
+import (
+	"io"
+
+	"github.com/ganyariya/go_monkey/evaluator"
+	"github.com/ganyariya/go_monkey/lexer"
+	"github.com/ganyariya/go_monkey/object"
+	"github.com/ganyariya/go_monkey/parser"
+)
+
+/*
+スクリプトファイルの内容 src を実行する
+構文エラーや実行時エラー（トレースバックつき）は errOut に書き出し、終了コードとして 1 を返す
+*/
+func RunScript(filename, src string, errOut io.Writer) int {
+	l := lexer.NewLexerWithFilename(filename, src)
+	p := parser.NewParser(l)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		printParseErrors(errOut, p.Errors())
+		return 1
+	}
+
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded := evaluator.ExpandMacros(program, macroEnv)
+
+	evaluated := evaluator.Eval(expanded, object.NewEnvironment())
+	if errObj, ok := evaluated.(*object.Error); ok {
+		io.WriteString(errOut, errObj.Traceback())
+		io.WriteString(errOut, "\n")
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package token
+
+import "fmt"
+
+/*
+ソースコード上の位置
+Line / Column は 1 始まり、Offset は 0 始まりのバイトオフセット
+Line が 0 の場合は「位置情報なし」（マクロなどで合成されたトークン）を表す
+*/
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+func (p Position) IsValid() bool { return p.Line > 0 }
+
+// file:line:col 形式（ファイル名がなければ line:col）
+func (p Position) String() string {
+	if !p.IsValid() {
+		if p.Filename != "" {
+			return p.Filename
+		}
+		return "-"
+	}
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
@@ -11,12 +11,19 @@ lexer などで必ず `token` package を引いて
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
+	COMMENT = "COMMENT" // `// ...` や `/* ... */`（構文解析器は文のトリビアとして保持する）
 
 	// 識別子 & リテラル
 	IDENTIFIER = "IDENTIFIER"
 	INT        = "INT"
+	FLOAT      = "FLOAT"
 	STRING     = "STRING"
 
+	// 補間を含む文字列 "a ${x} b ${y} c" は HEAD("a ") MIDDLE(" b ") TAIL(" c") に分割される
+	STRING_HEAD   = "STRING_HEAD"
+	STRING_MIDDLE = "STRING_MIDDLE"
+	STRING_TAIL   = "STRING_TAIL"
+
 	// 演算子
 	ASSIGN   = "ASSIGN"
 	PLUS     = "PLUS"
@@ -24,17 +31,44 @@ const (
 	BANG     = "BANG"
 	ASTERISK = "ASTERISK"
 	SLASH    = "SLASH"
+	PERCENT  = "PERCENT"
+	POWER    = "POWER" // **
+
+	// 複合代入演算子
+	PLUS_ASSIGN     = "PLUS_ASSIGN"     // +=
+	MINUS_ASSIGN    = "MINUS_ASSIGN"    // -=
+	ASTERISK_ASSIGN = "ASTERISK_ASSIGN" // *=
+	SLASH_ASSIGN    = "SLASH_ASSIGN"    // /=
+
+	// ビット演算子
+	AMPERSAND = "AMPERSAND" // &
+	PIPE      = "PIPE"      // |
+	PIPELINE  = "PIPELINE"  // |> （`x |> f(y)` は `f(x, y)`）
+	CARET     = "CARET"     // ^
+	TILDE     = "TILDE"     // ~
+	LSHIFT    = "LSHIFT"    // <<
+	RSHIFT    = "RSHIFT"    // >>
 
-	LT = "LT"
-	GT = "GT"
+	// 論理演算子（短絡評価）
+	AND = "AND" // &&
+	OR  = "OR"  // ||
+
+	LT    = "LT"
+	GT    = "GT"
+	LT_EQ = "LT_EQ" // <=
+	GT_EQ = "GT_EQ" // >=
 
 	EQ     = "EQ"
 	NOT_EQ = "NOT_EQ"
+	IS     = "IS" // 同一性（同じオブジェクトか）
 
 	// デリミタ
 	COMMA     = "COMMA"
 	SEMICOLON = "SEMICOLON"
 	COLON     = "COLON"
+	DOT       = "DOT"       // obj.field
+	ELLIPSIS  = "ELLIPSIS"  // ...
+	FAT_ARROW = "FAT_ARROW" // =>
 
 	LPAREN   = "LPAREN"
 	RPAREN   = "RPAREN"
@@ -48,10 +82,26 @@ const (
 	LET      = "LET"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	NULL     = "NULL"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
 
+	// ループ
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+
+	MATCH = "MATCH"
+
+	// 例外
+	THROW   = "THROW"
+	TRY     = "TRY"
+	CATCH   = "CATCH"
+	FINALLY = "FINALLY"
+
 	MACRO = "MACRO"
 )
 
@@ -62,9 +112,10 @@ const (
 type Token struct {
 	Type    TokenType // const の右辺値が入る
 	Literal string    // ソースコードにおける実際の値が入る
+	Pos     Position  // トークンの先頭文字の位置
 }
 
-func NewToken(tokenType TokenType, ch byte) Token {
+func NewToken(tokenType TokenType, ch rune) Token {
 	return Token{Type: tokenType, Literal: string(ch)}
 }
 
@@ -76,7 +127,22 @@ var keywords = map[string]TokenType{
 	"return": RETURN,
 	"true":   TRUE,
 	"false":  FALSE,
+	"null":   NULL,
+	"is":     IS,
 	"macro":  MACRO,
+
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+
+	"match": MATCH,
+
+	"throw":   THROW,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
 }
 
 // リテラルの値からその値がキーワードか調べて「タイプ」を返す